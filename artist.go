@@ -1,13 +1,30 @@
 package monstercat
 
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+type ArtistImageType string
+
+// artist image types.
+const (
+	ArtistProfile ArtistImageType = "photo"
+	ArtistBanner  ArtistImageType = "banner"
+)
+
 // Artist.
 type Artist struct {
-	CatalogRecordID string
-	ID              string
-	Name            string
-	Public          bool
-	Role            string
-	URI             string
+	CatalogRecordID string `json:"catalogRecordId,omitempty"`
+	ID              string `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Public          bool   `json:"public,omitempty"`
+	Role            string `json:"role,omitempty"`
+	URI             string `json:"uri,omitempty"`
+	ProfileFileID   string `json:"profileFileId,omitempty"`
+	ProfileImageURL string `json:"profileImageUrl,omitempty"`
+	BannerImageURL  string `json:"bannerImageUrl,omitempty"`
 }
 
 // Artist API Response.
@@ -29,5 +46,54 @@ func (r *artistAPIResponse) toArtist() Artist {
 		Public:          r.Public,
 		Role:            r.Role,
 		URI:             r.URI,
+		ProfileFileID:   r.ProfileFileID,
+		ProfileImageURL: buildArtistImageURL(r.URI, ArtistProfile),
+		BannerImageURL:  buildArtistImageURL(r.URI, ArtistBanner),
+	}
+}
+
+// GetArtistImageURL returns the CDX url of the artist image resized with the provided options.
+func (c *Client) GetArtistImageURL(ctx context.Context, artist Artist, imageType ArtistImageType, options ...ResizeOption) (string, error) {
+	imageURL, err := artistImageURL(artist, imageType)
+	if err != nil {
+		return "", err
+	}
+	return c.GetResizedImageURL(ctx, imageURL, options...)
+}
+
+// GetArtistImage returns the artist image resized with the provided options.
+func (c *Client) GetArtistImage(ctx context.Context, artist Artist, imageType ArtistImageType, options ...ResizeOption) (Image, error) {
+	imageURL, err := artistImageURL(artist, imageType)
+	if err != nil {
+		return Image{}, err
+	}
+
+	buf := new(bytes.Buffer)
+	img, err := c.writeImage(ctx, imageURL, buf, options...)
+	if err != nil {
+		return Image{}, err
+	}
+
+	img.Data = buf.Bytes()
+	return img, nil
+}
+
+func artistImageURL(artist Artist, imageType ArtistImageType) (string, error) {
+	if len(artist.URI) == 0 {
+		return "", fmt.Errorf("uri is empty for artist")
+	}
+
+	switch imageType {
+	case ArtistProfile, ArtistBanner:
+		return buildArtistImageURL(artist.URI, imageType), nil
+	default:
+		return "", fmt.Errorf("invalid artist image type")
+	}
+}
+
+func buildArtistImageURL(uri string, imageType ArtistImageType) string {
+	if len(uri) == 0 {
+		return ""
 	}
+	return fmt.Sprintf("%s/artist/%s/%s", webURL, uri, imageType)
 }
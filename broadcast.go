@@ -0,0 +1,267 @@
+package monstercat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSlowConsumer is returned to a broadcast reader that fell too far behind the others.
+	ErrSlowConsumer = errors.New("slow consumer evicted from broadcast")
+
+	errBroadcastClosed = errors.New("broadcast closed")
+)
+
+// Broadcast.
+//
+// Fans out a single upstream stream to any number of readers. At most bufSize
+// bytes are buffered between the slowest and the fastest reader. When the
+// buffer is full the upstream waits for the slowest readers, and once they
+// have held it up for longer than evictAfter they are evicted with
+// ErrSlowConsumer so the rest can carry on.
+type Broadcast struct {
+	src        io.ReadCloser
+	bufSize    int
+	evictAfter time.Duration
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	buf       []byte // bytes [start, start+len(buf)) of the upstream
+	start     int64
+	readers   map[*broadcastReader]struct{}
+	fullSince time.Time
+	err       error
+	closed    bool
+}
+
+// NewBroadcast starts fanning out src. It closes src once the stream ends or the broadcast is closed.
+func NewBroadcast(src io.ReadCloser, bufSize int, evictAfter time.Duration) (*Broadcast, error) {
+	if bufSize < 1 {
+		return nil, fmt.Errorf("buffer size must be positive")
+	}
+	if evictAfter <= 0 {
+		return nil, fmt.Errorf("evict after must be positive")
+	}
+
+	b := &Broadcast{
+		src:        src,
+		bufSize:    bufSize,
+		evictAfter: evictAfter,
+		buf:        make([]byte, 0, bufSize),
+		readers:    make(map[*broadcastReader]struct{}),
+	}
+	b.cond = sync.NewCond(&b.mu)
+
+	go b.pump()
+	return b, nil
+}
+
+// BroadcastTrack opens a single stream for the track and fans it out. Cancelling ctx ends the broadcast.
+func (c *Client) BroadcastTrack(ctx context.Context, track Track, bufSize int, evictAfter time.Duration) (*Broadcast, error) {
+	stream, err := c.GetTrackStream(ctx, track)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := NewBroadcast(stream, bufSize, evictAfter)
+	if err != nil {
+		stream.Close()
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// NewReader returns a reader starting at the oldest byte still buffered,
+// which is the start of the stream until the buffer first fills up.
+func (b *Broadcast) NewReader() (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, errBroadcastClosed
+	}
+
+	r := &broadcastReader{b: b, pos: b.start}
+	b.readers[r] = struct{}{}
+	return r, nil
+}
+
+// Close ends the broadcast for every reader and closes the upstream.
+func (b *Broadcast) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	if b.err == nil {
+		b.err = errBroadcastClosed
+	}
+	b.cond.Broadcast()
+	b.mu.Unlock()
+
+	return b.src.Close()
+}
+
+func (b *Broadcast) pump() {
+	defer b.src.Close()
+
+	chunk := make([]byte, 32*1024)
+	for {
+		b.mu.Lock()
+		free := b.waitForSpace()
+		b.mu.Unlock()
+		if free == 0 {
+			return
+		}
+
+		if free < len(chunk) {
+			chunk = chunk[:free]
+		}
+		n, err := b.src.Read(chunk)
+
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			return
+		}
+		b.buf = append(b.buf, chunk[:n]...)
+		if err != nil {
+			b.err = err
+		}
+		b.cond.Broadcast()
+		b.mu.Unlock()
+
+		if err != nil {
+			return
+		}
+		chunk = chunk[:cap(chunk)]
+	}
+}
+
+// waitForSpace blocks until the buffer has room and returns how much. It returns 0 once closed.
+func (b *Broadcast) waitForSpace() int {
+	for !b.closed {
+		b.trim()
+		if len(b.buf) < b.bufSize {
+			b.fullSince = time.Time{}
+			return b.bufSize - len(b.buf)
+		}
+
+		if b.fullSince.IsZero() {
+			b.fullSince = time.Now()
+		}
+
+		wait := b.evictAfter - time.Since(b.fullSince)
+		if wait <= 0 && b.evictSlowest() {
+			continue
+		}
+		if wait <= 0 {
+			// everyone is equally behind, give them another round.
+			wait = b.evictAfter
+			b.fullSince = time.Now()
+		}
+
+		timer := time.AfterFunc(wait, func() {
+			b.mu.Lock()
+			b.cond.Broadcast()
+			b.mu.Unlock()
+		})
+		b.cond.Wait()
+		timer.Stop()
+	}
+
+	return 0
+}
+
+// trim drops the bytes every reader has already consumed.
+func (b *Broadcast) trim() {
+	if len(b.readers) == 0 {
+		return
+	}
+
+	min := b.start + int64(len(b.buf))
+	for r := range b.readers {
+		if r.pos < min {
+			min = r.pos
+		}
+	}
+
+	if dropped := int(min - b.start); dropped > 0 {
+		b.buf = append(b.buf[:0], b.buf[dropped:]...)
+		b.start = min
+	}
+}
+
+// evictSlowest evicts the readers holding up the buffer, as long as someone else is ahead of them.
+func (b *Broadcast) evictSlowest() bool {
+	slow := make([]*broadcastReader, 0)
+	ahead := false
+	for r := range b.readers {
+		if r.pos == b.start {
+			slow = append(slow, r)
+		} else {
+			ahead = true
+		}
+	}
+
+	if !ahead || len(slow) == 0 {
+		return false
+	}
+
+	for _, r := range slow {
+		r.err = ErrSlowConsumer
+		delete(b.readers, r)
+	}
+	b.cond.Broadcast()
+	return true
+}
+
+type broadcastReader struct {
+	b   *Broadcast
+	pos int64
+	err error
+}
+
+func (r *broadcastReader) Read(p []byte) (int, error) {
+	b := r.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		if end := b.start + int64(len(b.buf)); r.pos < end {
+			n := copy(p, b.buf[r.pos-b.start:])
+			r.pos += int64(n)
+			b.cond.Broadcast()
+			return n, nil
+		}
+
+		if b.err != nil {
+			return 0, b.err
+		}
+
+		b.cond.Wait()
+	}
+}
+
+func (r *broadcastReader) Close() error {
+	b := r.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.err == nil {
+		r.err = io.ErrClosedPipe
+	}
+	delete(b.readers, r)
+	b.cond.Broadcast()
+	return nil
+}
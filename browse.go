@@ -0,0 +1,161 @@
+package monstercat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Mood.
+type Mood struct {
+	ID          string
+	URI         string
+	Name        string
+	Description string
+	CoverURL    string
+}
+
+// Mood Info.
+type MoodInfo struct {
+	ID          string
+	URI         string
+	Name        string
+	Description string
+	CoverURL    string
+	Tracks      TrackPage
+}
+
+// Genre.
+type Genre struct {
+	Name  string
+	Count int
+}
+
+// Mood API Response.
+type moodAPIResponse struct {
+	ID          string `json:"Id"`
+	URI         string `json:"URI"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	TileFileID  string `json:"TileFileId"`
+}
+
+// List Moods API Response.
+type listMoodsAPIResponse struct {
+	Moods struct {
+		Data []moodAPIResponse `json:"Data"`
+	} `json:"Moods"`
+}
+
+// Get Mood API Response.
+type getMoodAPIResponse struct {
+	Mood moodAPIResponse `json:"Mood"`
+}
+
+// Catalog Filters API Response.
+type catalogFiltersAPIResponse struct {
+	Genres []struct {
+		Name  string `json:"Name"`
+		Count int    `json:"Count"`
+	} `json:"Genres"`
+}
+
+func (r *moodAPIResponse) toMood() Mood {
+	return Mood{
+		ID:          r.ID,
+		URI:         r.URI,
+		Name:        r.Name,
+		Description: r.Description,
+		CoverURL:    buildMoodCoverURL(r.URI),
+	}
+}
+
+// ListMoods returns the editorial mood collections.
+func (c *Client) ListMoods(ctx context.Context) ([]Mood, error) {
+	req, err := c.makeRequest(ctx, "moods", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	apiResponse := new(listMoodsAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return nil, err
+	}
+
+	moods := make([]Mood, 0)
+	for _, result := range apiResponse.Moods.Data {
+		moods = append(moods, result.toMood())
+	}
+
+	return moods, nil
+}
+
+// GetMood returns the mood with the first page of its tracks, WithLimit and WithOffset control the page.
+func (c *Client) GetMood(ctx context.Context, uri string, opts ...Option) (MoodInfo, error) {
+	uri = strings.TrimSpace(uri)
+	if len(uri) == 0 {
+		return MoodInfo{}, fmt.Errorf("uri cannot be empty")
+	}
+
+	options := newOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	req, err := c.makeRequest(ctx, fmt.Sprintf("mood/%s", uri), nil)
+	if err != nil {
+		return MoodInfo{}, err
+	}
+
+	apiResponse := new(getMoodAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return MoodInfo{}, err
+	}
+
+	tracks, err := c.getTrackPage(ctx, fmt.Sprintf("mood/%s/catalog", uri), options)
+	if err != nil {
+		return MoodInfo{}, fmt.Errorf("error while getting tracks for mood: %w", err)
+	}
+
+	mood := apiResponse.Mood.toMood()
+	return MoodInfo{
+		ID:          mood.ID,
+		URI:         mood.URI,
+		Name:        mood.Name,
+		Description: mood.Description,
+		CoverURL:    mood.CoverURL,
+		Tracks:      tracks,
+	}, nil
+}
+
+// ListPublicPlaylists returns every public and editorial playlist.
+func (c *Client) ListPublicPlaylists(ctx context.Context) ([]Playlist, error) {
+	return c.listPlaylists(ctx, "playlists/public")
+}
+
+// ListGenres returns the catalog genres with the number of tracks in each.
+func (c *Client) ListGenres(ctx context.Context) ([]Genre, error) {
+	req, err := c.makeRequest(ctx, "catalog/filters", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	apiResponse := new(catalogFiltersAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return nil, err
+	}
+
+	genres := make([]Genre, 0)
+	for _, result := range apiResponse.Genres {
+		genres = append(genres, Genre{Name: result.Name, Count: result.Count})
+	}
+
+	return genres, nil
+}
+
+func buildMoodCoverURL(uri string) string {
+	if len(uri) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/mood/%s/tile", webURL, uri)
+}
@@ -0,0 +1,24 @@
+package monstercat
+
+import "strings"
+
+type clientOpts struct {
+	baseURL string
+}
+
+type ClientOption func(o *clientOpts)
+
+func newClientOpts() *clientOpts {
+	return &clientOpts{
+		baseURL: baseURL, // default is the production player api
+	}
+}
+
+// WithBaseURL points the client at another player api, such as staging.
+func WithBaseURL(u string) ClientOption {
+	return func(o *clientOpts) {
+		if u = strings.TrimRight(strings.TrimSpace(u), "/"); len(u) != 0 {
+			o.baseURL = u
+		}
+	}
+}
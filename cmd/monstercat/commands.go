@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ppalone/monstercat"
+	"github.com/ppalone/monstercat/export"
+)
+
+func runSearch(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "search", "<query>")
+	limit := fs.Int("limit", 25, "number of results (1-100)")
+	offset := fs.Int("offset", 0, "number of results to skip")
+	releaseType := fs.String("type", "", "release type: Single, EP or Album")
+	sort := fs.String("sort", "", "sort field, prefix with - for descending")
+	asJSON := fs.Bool("json", false, "write JSON")
+	exportAs := addExportFlags(fs)
+	all := fs.Bool("all", false, "with -format, export every page of results")
+	query, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	format, exportOpts, err := exportAs.parse()
+	if err != nil {
+		return err
+	}
+	if *all && len(format) == 0 {
+		return fmt.Errorf("-all needs -format")
+	}
+
+	opts := []monstercat.Option{
+		monstercat.WithLimit(*limit),
+		monstercat.WithOffset(*offset),
+		monstercat.WithSort(*sort),
+	}
+	if len(*releaseType) != 0 {
+		opts = append(opts, monstercat.WithReleaseType(monstercat.ReleaseType(*releaseType)))
+	}
+
+	res, err := e.client.SearchCatalog(ctx, strings.Join(query, " "), opts...)
+	if err != nil {
+		return err
+	}
+	e.remember(res.Tracks)
+
+	if len(format) != 0 {
+		return exportPages(ctx, e, res, *all, format, exportOpts)
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, res)
+	}
+
+	if err := writeTracks(e.stdout, res.Tracks); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "%d-%d of %d\n", res.Offset+min(1, res.Size), res.Offset+res.Size, res.Total)
+	return nil
+}
+
+func runRelease(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "release", "<catalog id or uuid>")
+	uuid := fs.Bool("uuid", false, "the id is a release uuid rather than a catalog id")
+	asJSON := fs.Bool("json", false, "write JSON")
+	exportAs := addExportFlags(fs)
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	format, exportOpts, err := exportAs.parse()
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		fs.Usage()
+		return fmt.Errorf("release takes one id")
+	}
+
+	opts := []monstercat.ReleaseOption{}
+	if *uuid {
+		opts = append(opts, monstercat.WithIdType(monstercat.UUID))
+	}
+
+	release, err := e.client.GetRelease(ctx, rest[0], opts...)
+	if err != nil {
+		return err
+	}
+	e.remember(release.Tracks)
+
+	if len(format) != 0 {
+		return export.WriteRelease(e.stdout, format, release, exportOpts...)
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, release)
+	}
+
+	err = writeFields(e.stdout, [][2]string{
+		{"Title", release.Title},
+		{"Type", release.Type},
+		{"Catalog ID", release.CatalogID},
+		{"Release ID", release.ID},
+		{"Released", formatDate(release.ReleaseDate)},
+		{"Cover", release.CoverURL},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout)
+	return writeTracks(e.stdout, release.Tracks)
+}
+
+func runTrack(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "track", "<release id> <track id>")
+	asJSON := fs.Bool("json", false, "write JSON")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	track, err := lookupTrack(ctx, e.client, fs, rest)
+	if err != nil {
+		return err
+	}
+	e.remember([]monstercat.Track{track})
+
+	if *asJSON {
+		return writeJSON(e.stdout, track)
+	}
+	return writeFields(e.stdout, trackFields(track))
+}
+
+func runArtist(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "artist", "<artist uri or name>")
+	asJSON := fs.Bool("json", false, "write JSON")
+	exportAs := addExportFlags(fs)
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	format, exportOpts, err := exportAs.parse()
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		fs.Usage()
+		return fmt.Errorf("artist takes an artist uri or name")
+	}
+
+	name := strings.Join(rest, " ")
+	res, err := e.client.SearchCatalog(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	// the catalog has no artist lookup, so pick the artist out of their tracks.
+	var artist *monstercat.Artist
+	tracks := make([]monstercat.Track, 0)
+	for _, track := range res.Tracks {
+		for _, a := range track.Artists {
+			if a.URI == name || strings.EqualFold(a.Name, name) {
+				if artist == nil {
+					found := a
+					artist = &found
+				}
+				tracks = append(tracks, track)
+				break
+			}
+		}
+	}
+
+	if artist == nil {
+		return fmt.Errorf("no artist found for %q", name)
+	}
+	e.remember(tracks)
+
+	if len(format) != 0 {
+		return export.WriteArtists(e.stdout, format, []monstercat.Artist{*artist}, exportOpts...)
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, struct {
+			Artist monstercat.Artist
+			Tracks []monstercat.Track
+		}{*artist, tracks})
+	}
+
+	err = writeFields(e.stdout, [][2]string{
+		{"Name", artist.Name},
+		{"URI", artist.URI},
+		{"Artist ID", artist.ID},
+		{"Photo", artist.ProfileImageURL},
+		{"Banner", artist.BannerImageURL},
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(e.stdout)
+	return writeTracks(e.stdout, tracks)
+}
+
+func runStreamURL(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "stream-url", "<release id> <track id>")
+	asJSON := fs.Bool("json", false, "write JSON")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 2 {
+		fs.Usage()
+		return fmt.Errorf("stream-url takes a release id and a track id")
+	}
+
+	track := monstercat.Track{ID: rest[1], Release: monstercat.Release{ID: rest[0]}}
+	u, err := e.client.GetTrackStreamURL(ctx, track)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, u)
+	}
+
+	fmt.Fprintln(e.stdout, u.URL)
+	if !u.Expires.IsZero() {
+		fmt.Fprintln(e.stderr, "expires", u.Expires.Local().Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+func runCover(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "cover", "<catalog id>")
+	width := fs.Int("width", e.profile.ImageWidth, "width in pixels")
+	encoding := fs.String("encoding", e.profile.ImageEncoding, "image encoding: jpeg, webp, png or avif")
+	output := fs.String("o", "", "save the image to this file instead of printing its url")
+	asJSON := fs.Bool("json", false, "write JSON")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		fs.Usage()
+		return fmt.Errorf("cover takes a catalog id")
+	}
+
+	release, err := e.client.GetRelease(ctx, rest[0])
+	if err != nil {
+		return err
+	}
+
+	opts := []monstercat.ResizeOption{
+		monstercat.WithWidth(*width),
+		monstercat.WithEncoding(monstercat.ImageEncoding(*encoding)),
+	}
+
+	if len(*output) == 0 {
+		u, err := e.client.GetResizedImageURL(ctx, release.CoverURL, opts...)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return writeJSON(e.stdout, map[string]string{"url": u})
+		}
+		fmt.Fprintln(e.stdout, u)
+		return nil
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	img, err := e.client.WriteCoverImage(ctx, release.Release(), f, opts...)
+	if err != nil {
+		f.Close()
+		os.Remove(*output)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if *asJSON {
+		return writeJSON(e.stdout, img)
+	}
+	fmt.Fprintf(e.stderr, "saved %s (%dx%d %s)\n", *output, img.Width, img.Height, img.MIMEType)
+	return nil
+}
+
+func runDownload(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "download", "<release id> <track id>")
+	output := fs.String("o", "", "output file, defaults to the profile filename template in its output directory")
+	tags := fs.Bool("tags", false, "write ID3 tags into the MP3 stream")
+	coverWidth := fs.Int("cover", 0, "embed the cover at this width, implies -tags")
+	format := fs.String("format", "", "download the licensed file: mp3_320, flac or wav, needs a signed in session (monstercat login or -session)")
+	session := fs.String("session", "", "session file to use instead of the profile session")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if len(*session) != 0 {
+		if err := e.client.LoadSession(*session); err != nil {
+			return err
+		}
+	}
+
+	track, err := lookupTrack(ctx, e.client, fs, rest)
+	if err != nil {
+		return err
+	}
+
+	ext := "mp3"
+	if len(*format) != 0 && *format != string(monstercat.DownloadMP3) {
+		ext = *format
+	}
+	path := *output
+	if len(path) == 0 {
+		if err := os.MkdirAll(e.profile.OutputDir, 0o755); err != nil {
+			return err
+		}
+		path = filepath.Join(e.profile.OutputDir, e.profile.filename(track, ext))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var n int64
+	if len(*format) != 0 {
+		var file io.ReadCloser
+		file, err = e.client.DownloadTrackFile(ctx, track, monstercat.DownloadFormat(*format))
+		if err == nil {
+			n, err = io.Copy(f, file)
+			file.Close()
+		}
+	} else {
+		opts := []monstercat.DownloadOption{}
+		if *tags {
+			opts = append(opts, monstercat.WithTags())
+		}
+		if *coverWidth > 0 {
+			opts = append(opts, monstercat.WithCoverArt(*coverWidth))
+		}
+		n, err = e.client.DownloadTrack(ctx, track, f, opts...)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+
+	fmt.Fprintf(e.stderr, "saved %s (%d bytes)\n", path, n)
+	return f.Close()
+}
+
+// exportPages exports the results, walking the remaining pages when all is set.
+func exportPages(ctx context.Context, e *env, res monstercat.SearchCatalogResults, all bool, format export.Format, opts []export.Option) error {
+	enc, err := export.NewTrackEncoder(e.stdout, format, opts...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := enc.Encode(res.Tracks...); err != nil {
+			return err
+		}
+		if !all || !res.HasNext {
+			break
+		}
+		if res, err = res.Next(ctx); err != nil {
+			return err
+		}
+		e.remember(res.Tracks)
+	}
+
+	return enc.Close()
+}
+
+// lookupTrack finds the track from its release and track id arguments.
+func lookupTrack(ctx context.Context, c *monstercat.Client, fs *flag.FlagSet, args []string) (monstercat.Track, error) {
+	if len(args) != 2 {
+		fs.Usage()
+		return monstercat.Track{}, fmt.Errorf("%s takes a release id and a track id", fs.Name())
+	}
+
+	res, err := c.SearchCatalog(ctx, "", monstercat.WithReleaseId(args[0]))
+	for err == nil {
+		for _, track := range res.Tracks {
+			if track.ID == args[1] {
+				return track, nil
+			}
+		}
+		if !res.HasNext {
+			break
+		}
+		res, err = res.Next(ctx)
+	}
+	if err != nil {
+		return monstercat.Track{}, err
+	}
+
+	return monstercat.Track{}, fmt.Errorf("track %s not found in release %s", args[1], args[0])
+}
+
+// parseArgs parses flags wherever they appear and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	rest := make([]string, 0)
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return rest, nil
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func safeFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ppalone/monstercat"
+	"github.com/ppalone/monstercat/export"
+)
+
+const bashCompletion = `# bash completion for monstercat, load with: source <(monstercat completion bash)
+_monstercat() {
+    local IFS=$'\n'
+    COMPREPLY=($(monstercat __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null | cut -f1))
+}
+complete -o default -F _monstercat monstercat
+`
+
+const zshCompletion = `#compdef monstercat
+# zsh completion for monstercat, load with: source <(monstercat completion zsh)
+_monstercat() {
+    local -a lines completions
+    local line
+    lines=("${(@f)$(monstercat __complete "${words[@]:1:$((CURRENT-1))}" 2>/dev/null)}")
+    for line in $lines; do
+        [[ -z $line ]] && continue
+        if [[ $line == *$'\t'* ]]; then
+            completions+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+        else
+            completions+=("${line//:/\\:}")
+        fi
+    done
+    _describe 'monstercat' completions
+}
+compdef _monstercat monstercat
+`
+
+const fishCompletion = `# fish completion for monstercat, load with: monstercat completion fish | source
+function __monstercat_complete
+    set -l args (commandline -opc)
+    monstercat __complete $args[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c monstercat -f -a '(__monstercat_complete)'
+`
+
+var completionScripts = map[string]string{
+	"bash": bashCompletion,
+	"zsh":  zshCompletion,
+	"fish": fishCompletion,
+}
+
+func runCompletion(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "completion", "<bash | zsh | fish>")
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		fs.Usage()
+		return fmt.Errorf("completion takes a shell: bash, zsh or fish")
+	}
+
+	script, ok := completionScripts[rest[0]]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q, one of: bash, zsh, fish", rest[0])
+	}
+
+	fmt.Fprint(e.stdout, script)
+	return nil
+}
+
+// runComplete is the hidden command the completion scripts call with the words
+// typed so far, the last one being the word under the cursor. It prints one
+// candidate per line, optionally followed by a tab and a description.
+func runComplete(ctx context.Context, e *env, args []string) error {
+	seen := &seenCache{}
+	if len(e.seenPath) != 0 {
+		if s, err := loadSeen(e.seenPath); err == nil {
+			seen = s
+		}
+	}
+
+	for _, c := range complete(e, seen, args) {
+		fmt.Fprintln(e.stdout, c)
+	}
+	return nil
+}
+
+// flagValues are the fixed values of flags, completed after the flag. Keys
+// prefixed with a command take precedence for that command.
+var flagValues = map[string][]string{
+	"type":            {string(monstercat.ReleaseSingle), string(monstercat.ReleaseEP), string(monstercat.ReleaseAlbum)},
+	"encoding":        {string(monstercat.JPEG), string(monstercat.WEBP), string(monstercat.PNG), string(monstercat.AVIF)},
+	"format":          {string(export.CSV), string(export.JSON), string(export.NDJSON)},
+	"download format": {string(monstercat.DownloadMP3), string(monstercat.DownloadFLAC), string(monstercat.DownloadWAV)},
+}
+
+// flagValuesFor returns the values of the flag of the command, or nil when they are not fixed.
+func flagValuesFor(cmd, flag string) []string {
+	if strings.Contains(flag, "=") {
+		return nil
+	}
+	name := strings.TrimLeft(flag, "-")
+	if values, ok := flagValues[cmd+" "+name]; ok {
+		return values
+	}
+	return flagValues[name]
+}
+
+// commandFlags returns the flag set of the command, built by running it with
+// -h and its output discarded, or nil when the command has none.
+func commandFlags(e *env, name string) *flag.FlagSet {
+	cmd, ok := commands[name]
+	if !ok || name == "__complete" {
+		return nil
+	}
+
+	var fs *flag.FlagSet
+	probe := *e
+	probe.stdout, probe.stderr = io.Discard, io.Discard
+	probe.onFlagSet = func(f *flag.FlagSet) { fs = f }
+	cmd(context.Background(), &probe, []string{"-h"})
+	return fs
+}
+
+// takesValue reports whether the flag is followed by its value as the next word.
+func takesValue(fs *flag.FlagSet, word string) bool {
+	if fs == nil || strings.Contains(word, "=") {
+		return false
+	}
+	f := fs.Lookup(strings.TrimLeft(word, "-"))
+	if f == nil {
+		return false
+	}
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return false
+	}
+	return true
+}
+
+// complete returns the candidates for the last word of words.
+func complete(e *env, seen *seenCache, words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+
+	// the global profile flag comes before the command.
+	for len(words) > 1 && strings.HasPrefix(words[0], "-") {
+		name := strings.TrimLeft(words[0], "-")
+		words = words[1:]
+		if name == "profile" && len(words) > 1 {
+			words = words[1:]
+		}
+	}
+
+	cur := words[len(words)-1]
+	if len(words) == 1 {
+		candidates := make([]string, 0, len(commandHelp))
+		for _, c := range commandHelp {
+			candidates = append(candidates, c.name+"\t"+c.help)
+		}
+		return filterCandidates(candidates, cur)
+	}
+
+	cmd, prev := words[0], words[len(words)-2]
+	if strings.HasPrefix(cur, "-") {
+		return []string{}
+	}
+
+	fs := commandFlags(e, cmd)
+	if strings.HasPrefix(prev, "-") && takesValue(fs, prev) {
+		if cmd == "sync" && strings.TrimLeft(prev, "-") == "artist" {
+			return filterCandidates(artistNames(seen), cur)
+		}
+		// free form values, such as widths and paths, have nothing to offer.
+		return filterCandidates(flagValuesFor(cmd, prev), cur)
+	}
+
+	// positional arguments typed before the cursor, skipping flags and their values.
+	pos := make([]string, 0)
+	uuid := false
+	for i := 1; i < len(words)-1; i++ {
+		if strings.HasPrefix(words[i], "-") {
+			if name, value, ok := strings.Cut(strings.TrimLeft(words[i], "-"), "="); name == "uuid" {
+				uuid = !ok || value != "false"
+			}
+			if takesValue(fs, words[i]) {
+				i++
+			}
+			continue
+		}
+		pos = append(pos, words[i])
+	}
+
+	candidates := make([]string, 0)
+	switch cmd {
+	case "release", "cover":
+		if len(pos) == 0 {
+			for _, r := range seen.Releases {
+				switch {
+				case cmd == "release" && uuid:
+					// release -uuid takes the release uuid rather than the catalog id.
+					candidates = append(candidates, r.ID+"\t"+joinNonEmpty(" ", r.CatalogID, r.describe()))
+				case len(r.CatalogID) != 0:
+					candidates = append(candidates, r.CatalogID+"\t"+r.describe())
+				}
+			}
+		}
+	case "track", "stream-url", "download":
+		switch len(pos) {
+		case 0:
+			for _, r := range seen.Releases {
+				candidates = append(candidates, r.ID+"\t"+joinNonEmpty(" ", r.CatalogID, r.describe()))
+			}
+		case 1:
+			for _, r := range seen.Releases {
+				if r.ID != pos[0] {
+					continue
+				}
+				for _, t := range r.Tracks {
+					candidates = append(candidates, t.ID+"\t"+t.Title)
+				}
+			}
+		}
+	case "artist":
+		if len(pos) == 0 {
+			for _, a := range seen.Artists {
+				candidates = append(candidates, a.URI+"\t"+a.Name)
+			}
+		}
+	case "config":
+		switch {
+		case len(pos) == 0:
+			candidates = append(candidates, "path", "profiles", "use", "get", "set", "unset")
+		case len(pos) == 1 && (pos[0] == "get" || pos[0] == "set" || pos[0] == "unset"):
+			for _, key := range sortedProfileKeys() {
+				candidates = append(candidates, key+"\t"+profileKeys[key].help)
+			}
+		case len(pos) == 1 && pos[0] == "use":
+			if cfg, err := loadConfig(e.configPath); err == nil {
+				candidates = append(candidates, defaultProfile)
+				for name := range cfg.Profiles {
+					if name != defaultProfile {
+						candidates = append(candidates, name)
+					}
+				}
+			}
+		}
+	case "completion":
+		if len(pos) == 0 {
+			candidates = append(candidates, "bash", "zsh", "fish")
+		}
+	}
+
+	return filterCandidates(candidates, cur)
+}
+
+func (r seenRelease) describe() string {
+	return joinNonEmpty(" - ", r.Title, r.Artists)
+}
+
+func artistNames(seen *seenCache) []string {
+	names := make([]string, 0, len(seen.Artists))
+	for _, a := range seen.Artists {
+		if len(a.Name) != 0 {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// filterCandidates keeps the candidates whose value starts with prefix.
+func filterCandidates(candidates []string, prefix string) []string {
+	filtered := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		value, _, _ := strings.Cut(c, "\t")
+		if strings.HasPrefix(value, prefix) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	nonEmpty := make([]string, 0, len(values))
+	for _, v := range values {
+		if len(v) != 0 {
+			nonEmpty = append(nonEmpty, v)
+		}
+	}
+	return strings.Join(nonEmpty, sep)
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ppalone/monstercat"
+)
+
+const defaultProfile = "default"
+
+// config is the persisted CLI configuration.
+type config struct {
+	Profile  string             `json:"profile,omitempty"`
+	Profiles map[string]profile `json:"profiles,omitempty"`
+}
+
+// profile holds the settings of one named profile. Zero values fall back to defaults.
+type profile struct {
+	OutputDir        string  `json:"outputDir,omitempty"`
+	FilenameTemplate string  `json:"filenameTemplate,omitempty"`
+	ImageWidth       int     `json:"imageWidth,omitempty"`
+	ImageEncoding    string  `json:"imageEncoding,omitempty"`
+	RateLimit        float64 `json:"rateLimit,omitempty"`
+	BaseURL          string  `json:"baseURL,omitempty"`
+	Session          string  `json:"session,omitempty"`
+}
+
+// profileKey describes a settable profile field.
+type profileKey struct {
+	env  string
+	help string
+	get  func(p *profile) string
+	set  func(p *profile, v string) error
+}
+
+var profileKeys = map[string]profileKey{
+	"output-dir": {
+		env:  "MONSTERCAT_OUTPUT_DIR",
+		help: "directory downloads and syncs are written to",
+		get:  func(p *profile) string { return p.OutputDir },
+		set:  func(p *profile, v string) error { p.OutputDir = v; return nil },
+	},
+	"filename-template": {
+		env:  "MONSTERCAT_FILENAME_TEMPLATE",
+		help: "download file name, with {artists} {title} {release} {catalog} {track} {ext}",
+		get:  func(p *profile) string { return p.FilenameTemplate },
+		set:  func(p *profile, v string) error { p.FilenameTemplate = v; return nil },
+	},
+	"image-width": {
+		env:  "MONSTERCAT_IMAGE_WIDTH",
+		help: "cover width in pixels",
+		get:  func(p *profile) string { return formatInt(p.ImageWidth) },
+		set: func(p *profile, v string) error {
+			if len(v) == 0 {
+				p.ImageWidth = 0
+				return nil
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("image-width must be a positive number")
+			}
+			p.ImageWidth = n
+			return nil
+		},
+	},
+	"image-encoding": {
+		env:  "MONSTERCAT_IMAGE_ENCODING",
+		help: "cover encoding: jpeg, webp, png or avif",
+		get:  func(p *profile) string { return p.ImageEncoding },
+		set:  func(p *profile, v string) error { p.ImageEncoding = v; return nil },
+	},
+	"rate-limit": {
+		env:  "MONSTERCAT_RATE_LIMIT",
+		help: "maximum requests per second, 0 for no limit",
+		get: func(p *profile) string {
+			if p.RateLimit == 0 {
+				return ""
+			}
+			return strconv.FormatFloat(p.RateLimit, 'f', -1, 64)
+		},
+		set: func(p *profile, v string) error {
+			if len(v) == 0 {
+				p.RateLimit = 0
+				return nil
+			}
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil || n < 0 {
+				return fmt.Errorf("rate-limit must be a positive number")
+			}
+			p.RateLimit = n
+			return nil
+		},
+	},
+	"base-url": {
+		env:  "MONSTERCAT_BASE_URL",
+		help: "player api base url, for staging",
+		get:  func(p *profile) string { return p.BaseURL },
+		set:  func(p *profile, v string) error { p.BaseURL = v; return nil },
+	},
+	"session": {
+		env:  "MONSTERCAT_SESSION",
+		help: "session file of the signed in account",
+		get:  func(p *profile) string { return p.Session },
+		set:  func(p *profile, v string) error { p.Session = v; return nil },
+	},
+}
+
+// configDir returns the XDG config directory of the CLI.
+func configDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "monstercat"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "monstercat"), nil
+}
+
+// configPath returns the config file path, MONSTERCAT_CONFIG overrides it.
+func configPath() (string, error) {
+	if path := os.Getenv("MONSTERCAT_CONFIG"); len(path) != 0 {
+		return path, nil
+	}
+
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+func loadConfig(path string) (*config, error) {
+	cfg := &config{Profiles: make(map[string]profile)}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]profile)
+	}
+	return cfg, nil
+}
+
+func saveConfig(path string, cfg *config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o600)
+}
+
+// resolveProfile returns the settings of the named profile with env overrides and defaults applied.
+func resolveProfile(cfg *config, name string) (profile, error) {
+	p := cfg.Profiles[name]
+
+	for _, k := range profileKeys {
+		if v := os.Getenv(k.env); len(v) != 0 {
+			if err := k.set(&p, v); err != nil {
+				return profile{}, fmt.Errorf("%s: %w", k.env, err)
+			}
+		}
+	}
+
+	if len(p.OutputDir) == 0 {
+		p.OutputDir = "."
+	}
+	if len(p.FilenameTemplate) == 0 {
+		p.FilenameTemplate = "{artists} - {title}.{ext}"
+	}
+	if p.ImageWidth == 0 {
+		p.ImageWidth = 600
+	}
+	if len(p.ImageEncoding) == 0 {
+		p.ImageEncoding = string(monstercat.JPEG)
+	}
+	if len(p.Session) == 0 {
+		dir, err := configDir()
+		if err != nil {
+			return profile{}, err
+		}
+		p.Session = filepath.Join(dir, "sessions", name+".json")
+	}
+
+	return p, nil
+}
+
+// newClient returns a client for the profile, signed in when its session file exists.
+func newClient(p profile) (*monstercat.Client, error) {
+	httpClient := &http.Client{}
+	if p.RateLimit > 0 {
+		httpClient.Transport = newRateLimitTransport(http.DefaultTransport, p.RateLimit)
+	}
+
+	c := monstercat.NewClient(httpClient, monstercat.WithBaseURL(p.BaseURL))
+	if err := c.LoadSession(p.Session); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return c, nil
+}
+
+// filename renders the profile filename template for the track.
+func (p profile) filename(track monstercat.Track, ext string) string {
+	r := strings.NewReplacer(
+		"{artists}", track.ArtistsTitle,
+		"{title}", track.Title,
+		"{release}", track.Release.Title,
+		"{catalog}", track.Release.CatalogID,
+		"{track}", formatInt(track.TrackNumber),
+		"{ext}", ext,
+	)
+	return safeFilename(r.Replace(p.FilenameTemplate))
+}
+
+// rateLimitTransport spaces requests out to at most a fixed rate.
+type rateLimitTransport struct {
+	next     http.RoundTripper
+	interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func newRateLimitTransport(next http.RoundTripper, perSecond float64) *rateLimitTransport {
+	return &rateLimitTransport{
+		next:     next,
+		interval: time.Duration(float64(time.Second) / perSecond),
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	wait := time.Until(t.last.Add(t.interval))
+	if wait < 0 {
+		wait = 0
+	}
+	t.last = time.Now().Add(wait)
+	t.mu.Unlock()
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+func runConfig(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "config", "[path | profiles | use <profile> | get <key> | set <key> <value> | unset <key>]")
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "usage: monstercat config [path | profiles | use <profile> | get <key> | set <key> <value> | unset <key>]")
+		fmt.Fprintln(e.stderr, "\nkeys, settable through the environment too:")
+		for _, key := range sortedProfileKeys() {
+			fmt.Fprintf(e.stderr, "  %-18s %s (%s)\n", key, profileKeys[key].help, profileKeys[key].env)
+		}
+	}
+	rest, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(e.configPath)
+	if err != nil {
+		return err
+	}
+
+	if len(rest) == 0 {
+		// show the effective settings of the current profile.
+		fields := [][2]string{{"profile", e.profileName}, {"config", e.configPath}}
+		for _, key := range sortedProfileKeys() {
+			fields = append(fields, [2]string{key, profileKeys[key].get(&e.profile)})
+		}
+		return writeFields(e.stdout, fields)
+	}
+
+	switch rest[0] {
+	case "path":
+		fmt.Fprintln(e.stdout, e.configPath)
+		return nil
+	case "profiles":
+		names := []string{defaultProfile}
+		for name := range cfg.Profiles {
+			if name != defaultProfile {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names[1:])
+		for _, name := range names {
+			marker := " "
+			if name == e.profileName {
+				marker = "*"
+			}
+			fmt.Fprintf(e.stdout, "%s %s\n", marker, name)
+		}
+		return nil
+	case "use":
+		if len(rest) != 2 {
+			return fmt.Errorf("config use takes a profile name")
+		}
+		cfg.Profile = rest[1]
+		return saveConfig(e.configPath, cfg)
+	case "get", "set", "unset":
+	default:
+		return fmt.Errorf("unknown config action %q", rest[0])
+	}
+
+	if len(rest) < 2 {
+		return fmt.Errorf("config %s takes a key, one of: %s", rest[0], strings.Join(sortedProfileKeys(), ", "))
+	}
+	k, ok := profileKeys[rest[1]]
+	if !ok {
+		return fmt.Errorf("unknown key %q, one of: %s", rest[1], strings.Join(sortedProfileKeys(), ", "))
+	}
+
+	p := cfg.Profiles[e.profileName]
+	switch rest[0] {
+	case "get":
+		fmt.Fprintln(e.stdout, k.get(&e.profile))
+		return nil
+	case "set":
+		if len(rest) != 3 {
+			return fmt.Errorf("config set takes a key and a value")
+		}
+		if err := k.set(&p, rest[2]); err != nil {
+			return err
+		}
+	case "unset":
+		if err := k.set(&p, ""); err != nil {
+			return err
+		}
+	}
+
+	cfg.Profiles[e.profileName] = p
+	return saveConfig(e.configPath, cfg)
+}
+
+func sortedProfileKeys() []string {
+	keys := make([]string, 0, len(profileKeys))
+	for key := range profileKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
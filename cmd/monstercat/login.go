@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ppalone/monstercat"
+)
+
+func runLogin(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "login", "")
+	email := fs.String("email", "", "account email (required)")
+	code := fs.String("code", "", "two factor code, prompted for when needed")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if len(*email) == 0 {
+		fs.Usage()
+		return fmt.Errorf("login needs --email")
+	}
+
+	in := bufio.NewReader(e.stdin)
+	password := os.Getenv("MONSTERCAT_PASSWORD")
+	if len(password) == 0 {
+		fmt.Fprint(e.stderr, "password: ")
+		password = readPassword(e, in)
+	}
+
+	err := e.client.Login(ctx, *email, password)
+	var twoFactor *monstercat.TwoFactorError
+	if errors.As(err, &twoFactor) {
+		if len(*code) == 0 {
+			fmt.Fprintf(e.stderr, "%s code: ", twoFactor.Method)
+			*code = readLine(in)
+		}
+		err = e.client.VerifyTwoFactor(ctx, twoFactor.Method, *code)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(e.profile.Session), 0o700); err != nil {
+		return err
+	}
+	if err := e.client.SaveSession(e.profile.Session); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(e.stderr, "signed in, session saved for profile %s\n", e.profileName)
+	return nil
+}
+
+func runLogout(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "logout", "")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	err := e.client.Logout(ctx)
+	if removeErr := os.Remove(e.profile.Session); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+		return removeErr
+	}
+	return err
+}
+
+// readPassword reads a line without echoing it when stdin is a terminal.
+func readPassword(e *env, in *bufio.Reader) string {
+	f, ok := e.stdin.(*os.File)
+	if !ok {
+		return readLine(in)
+	}
+
+	restore, err := disableEcho(int(f.Fd()))
+	if err != nil {
+		// not a terminal, such as piped input.
+		return readLine(in)
+	}
+	defer restore()
+
+	password := readLine(in)
+	// the newline typed after the password is not echoed either.
+	fmt.Fprintln(e.stderr)
+	return password
+}
+
+func readLine(r *bufio.Reader) string {
+	line, _ := r.ReadString('\n')
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,180 @@
+// Command monstercat searches, inspects and downloads from the Monstercat catalog.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/ppalone/monstercat"
+)
+
+// commandHelp lists the commands shown in the usage and offered by completion.
+var commandHelp = []struct{ name, help string }{
+	{"search", "search the catalog"},
+	{"release", "show a release and its tracks"},
+	{"track", "show a track"},
+	{"artist", "show an artist and their tracks"},
+	{"stream-url", "print the signed stream url of a track"},
+	{"cover", "print or save the cover of a release"},
+	{"download", "download a track"},
+	{"browse", "browse the catalog in a full screen terminal ui"},
+	{"sync", "mirror an artist's tracks into a directory"},
+	{"login", "sign in and save the session to the profile"},
+	{"logout", "sign out and remove the saved session"},
+	{"config", "view or edit the configuration"},
+	{"completion", "print the shell completion script"},
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, "usage: monstercat [--profile name] <command> [flags] [args]\n\ncommands:\n")
+	for _, c := range commandHelp {
+		fmt.Fprintf(w, "  %-11s %s\n", c.name, c.help)
+	}
+	fmt.Fprint(w, "\nrun \"monstercat <command> -h\" for the flags of a command.\n")
+}
+
+// command runs a subcommand with its arguments.
+type command func(ctx context.Context, env *env, args []string) error
+
+// env is what every command runs against.
+type env struct {
+	client      *monstercat.Client
+	profile     profile
+	profileName string
+	configPath  string
+	seenPath    string
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
+
+	// onFlagSet, when set, is called with the flag set of the command, so
+	// completion can tell the flags taking values from the boolean ones.
+	onFlagSet func(*flag.FlagSet)
+}
+
+var commands = map[string]command{
+	"search":     runSearch,
+	"release":    runRelease,
+	"track":      runTrack,
+	"artist":     runArtist,
+	"stream-url": runStreamURL,
+	"cover":      runCover,
+	"download":   runDownload,
+	"browse":     runBrowse,
+	"sync":       runSync,
+	"login":      runLogin,
+	"logout":     runLogout,
+	"config":     runConfig,
+	"completion": runCompletion,
+}
+
+func init() {
+	// registered here as completion looks up the other commands.
+	commands["__complete"] = runComplete
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e, args, err := newEnv(os.Args[1:])
+	if err == nil {
+		err = run(ctx, e, args)
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "monstercat:", err)
+		os.Exit(1)
+	}
+}
+
+// newEnv loads the configuration of the profile picked by the global flags and returns the remaining arguments.
+func newEnv(args []string) (*env, []string, error) {
+	fs := flag.NewFlagSet("monstercat", flag.ContinueOnError)
+	fs.Usage = func() { printUsage(os.Stderr) }
+	profileName := fs.String("profile", os.Getenv("MONSTERCAT_PROFILE"), "configuration profile")
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	name := *profileName
+	if len(name) == 0 {
+		name = cfg.Profile
+	}
+	if len(name) == 0 {
+		name = defaultProfile
+	}
+
+	p, err := resolveProfile(cfg, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := newClient(p)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// without a cache directory completion just has nothing to offer.
+	seen, _ := seenPath()
+
+	return &env{
+		client:      c,
+		profile:     p,
+		profileName: name,
+		configPath:  path,
+		seenPath:    seen,
+		stdin:       os.Stdin,
+		stdout:      os.Stdout,
+		stderr:      os.Stderr,
+	}, fs.Args(), nil
+}
+
+func run(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		printUsage(e.stderr)
+		if len(args) == 0 {
+			return fmt.Errorf("missing command")
+		}
+		return nil
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		printUsage(e.stderr)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	return cmd(ctx, e, args[1:])
+}
+
+// newFlagSet returns a flag set for the command that writes its usage to stderr.
+func newFlagSet(e *env, name string, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: monstercat %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	if e.onFlagSet != nil {
+		e.onFlagSet(fs)
+	}
+	return fs
+}
@@ -0,0 +1,444 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ppalone/monstercat"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+const catalogResponse = `{"Limit": 25, "Offset": 0, "Total": 1, "Data": [{
+	"Id": "track", "Title": "Emoji", "ArtistsTitle": "Pegboard Nerds", "BPM": 128, "Duration": 245,
+	"GenreSecondary": "Electro", "Artists": [{"Name": "Pegboard Nerds", "URI": "pegboardnerds", "Role": "Primary"}],
+	"Release": {"Id": "release", "CatalogId": "MCS123", "Title": "Emoji"}
+}]}`
+
+func newTestEnv() (*env, *bytes.Buffer) {
+	return newTestEnvWithCatalog(func() string { return catalogResponse })
+}
+
+// newTestEnvWithCatalog serves catalog() for catalog requests and fake audio for streams.
+func newTestEnvWithCatalog(catalog func() string) (*env, *bytes.Buffer) {
+	stdout := new(bytes.Buffer)
+	c := monstercat.NewClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := catalog()
+			if strings.Contains(req.URL.Path, "/track-stream/") {
+				body = "audio-frames"
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	})
+	p, _ := resolveProfile(&config{}, defaultProfile)
+	return &env{client: c, profile: p, profileName: defaultProfile, stdout: stdout, stderr: io.Discard}, stdout
+}
+
+func Test_Run(t *testing.T) {
+	t.Run("with search table", func(t *testing.T) {
+		e, stdout := newTestEnv()
+		err := run(context.Background(), e, []string{"search", "pegboard", "nerds"})
+		assert.NoError(t, err)
+		assert.Contains(t, stdout.String(), "TITLE")
+		assert.Contains(t, stdout.String(), "Emoji")
+		assert.Contains(t, stdout.String(), "4:05")
+	})
+
+	t.Run("with search json", func(t *testing.T) {
+		e, stdout := newTestEnv()
+		err := run(context.Background(), e, []string{"search", "pegboard", "--json"})
+		assert.NoError(t, err)
+
+		res := monstercat.SearchCatalogResults{}
+		assert.NoError(t, json.Unmarshal(stdout.Bytes(), &res))
+		assert.Len(t, res.Tracks, 1)
+	})
+
+	t.Run("with search export", func(t *testing.T) {
+		e, stdout := newTestEnv()
+		err := run(context.Background(), e, []string{"search", "pegboard", "--format", "csv", "--columns", "title,artist_uris,bpm"})
+		assert.NoError(t, err)
+		assert.Equal(t, "title,artist_uris,bpm\nEmoji,pegboardnerds,128\n", stdout.String())
+
+		err = run(context.Background(), e, []string{"search", "pegboard", "--all"})
+		assert.ErrorContains(t, err, "-all needs -format")
+
+		err = run(context.Background(), e, []string{"search", "pegboard", "--format", "xml"})
+		assert.ErrorContains(t, err, "unsupported export format")
+	})
+
+	t.Run("with search export of every page", func(t *testing.T) {
+		pages := []string{
+			strings.Replace(catalogResponse, `"Total": 1`, `"Total": 26`, 1),
+			strings.Replace(strings.Replace(catalogResponse, `"Total": 1`, `"Total": 26`, 1), `"Offset": 0`, `"Offset": 25`, 1),
+		}
+		e, stdout := newTestEnvWithCatalog(func() string {
+			page := pages[0]
+			pages = pages[1:]
+			return page
+		})
+		err := run(context.Background(), e, []string{"search", "pegboard", "--format", "ndjson", "--all"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(stdout.String(), "\n"))
+	})
+
+	t.Run("with artist export", func(t *testing.T) {
+		e, stdout := newTestEnv()
+		err := run(context.Background(), e, []string{"artist", "pegboardnerds", "--format", "csv", "--columns", "uri,name"})
+		assert.NoError(t, err)
+		assert.Equal(t, "uri,name\npegboardnerds,Pegboard Nerds\n", stdout.String())
+	})
+
+	t.Run("with artist", func(t *testing.T) {
+		e, stdout := newTestEnv()
+		err := run(context.Background(), e, []string{"artist", "pegboardnerds"})
+		assert.NoError(t, err)
+		assert.Contains(t, stdout.String(), "Pegboard Nerds")
+	})
+
+	t.Run("with track", func(t *testing.T) {
+		e, stdout := newTestEnv()
+		err := run(context.Background(), e, []string{"track", "release", "track"})
+		assert.NoError(t, err)
+		assert.Contains(t, stdout.String(), "MCS123")
+
+		err = run(context.Background(), e, []string{"track", "release", "missing"})
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("with failed cover", func(t *testing.T) {
+		e := newTUITestEnv(t)
+		path := filepath.Join(t.TempDir(), "cover.jpg")
+		err := run(context.Background(), e, []string{"cover", "MCS123", "-o", path})
+		assert.Error(t, err)
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("with unknown command", func(t *testing.T) {
+		e, _ := newTestEnv()
+		err := run(context.Background(), e, []string{"play"})
+		assert.ErrorContains(t, err, "unknown command")
+	})
+}
+
+func Test_Sync(t *testing.T) {
+	catalog := catalogResponse
+	e, stdout := newTestEnvWithCatalog(func() string { return catalog })
+	dir := t.TempDir()
+	args := []string{"sync", "--artist", "Pegboard Nerds", "--dir", dir, "--cover", "0"}
+
+	assert.NoError(t, run(context.Background(), e, args))
+	assert.Contains(t, stdout.String(), "added     Pegboard Nerds - Emoji.mp3")
+
+	b, err := os.ReadFile(filepath.Join(dir, "Pegboard Nerds - Emoji.mp3"))
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(b, []byte("ID3")))
+	assert.FileExists(t, filepath.Join(dir, manifestName))
+
+	t.Run("rerun downloads nothing", func(t *testing.T) {
+		stdout.Reset()
+		assert.NoError(t, run(context.Background(), e, args))
+		assert.Empty(t, stdout.String())
+	})
+
+	t.Run("reports retitled tracks", func(t *testing.T) {
+		stdout.Reset()
+		catalog = strings.Replace(catalogResponse, `"Title": "Emoji", "ArtistsTitle"`, `"Title": "Emoji (VIP)", "ArtistsTitle"`, 1)
+		assert.NoError(t, run(context.Background(), e, args))
+		assert.Contains(t, stdout.String(), "retitled")
+		assert.FileExists(t, filepath.Join(dir, "Pegboard Nerds - Emoji.mp3"))
+	})
+
+	t.Run("reports deleted tracks", func(t *testing.T) {
+		stdout.Reset()
+		catalog = `{"Limit": 25, "Offset": 0, "Total": 0, "Data": []}`
+		assert.NoError(t, run(context.Background(), e, args))
+		assert.Contains(t, stdout.String(), "missing")
+		assert.FileExists(t, filepath.Join(dir, "Pegboard Nerds - Emoji.mp3"))
+	})
+
+	t.Run("with tracks sharing a name", func(t *testing.T) {
+		stdout.Reset()
+		catalog = `{"Limit": 25, "Offset": 0, "Total": 1, "Data": [{
+			"Id": "remaster", "Title": "Emoji", "ArtistsTitle": "Pegboard Nerds",
+			"Artists": [{"Name": "Pegboard Nerds", "URI": "pegboardnerds", "Role": "Primary"}],
+			"Release": {"Id": "compilation", "CatalogId": "MCB456", "Title": "Best of"}
+		}]}`
+		assert.NoError(t, run(context.Background(), e, args))
+		assert.Contains(t, stdout.String(), "added     Pegboard Nerds - Emoji (MCB456).mp3")
+		assert.FileExists(t, filepath.Join(dir, "Pegboard Nerds - Emoji.mp3"))
+		assert.FileExists(t, filepath.Join(dir, "Pegboard Nerds - Emoji (MCB456).mp3"))
+	})
+}
+
+func Test_ReadPassword(t *testing.T) {
+	t.Run("with piped stdin", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		assert.NoError(t, err)
+		defer r.Close()
+
+		_, err = io.WriteString(w, "hunter2\n")
+		assert.NoError(t, err)
+		w.Close()
+
+		e, _ := newTestEnv()
+		e.stdin = r
+		assert.Equal(t, "hunter2", readPassword(e, bufio.NewReader(e.stdin)))
+	})
+}
+
+func Test_Config(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("MONSTERCAT_CONFIG", "")
+	t.Setenv("MONSTERCAT_IMAGE_WIDTH", "")
+
+	newEnvFor := func(t *testing.T, args ...string) (*env, []string, *bytes.Buffer) {
+		e, rest, err := newEnv(args)
+		assert.NoError(t, err)
+		stdout := new(bytes.Buffer)
+		e.stdout = stdout
+		e.stderr = io.Discard
+		return e, rest, stdout
+	}
+
+	t.Run("with defaults", func(t *testing.T) {
+		e, _, _ := newEnvFor(t)
+		assert.Equal(t, filepath.Join(dir, "monstercat", "config.json"), e.configPath)
+		assert.Equal(t, defaultProfile, e.profileName)
+		assert.Equal(t, 600, e.profile.ImageWidth)
+		assert.Equal(t, filepath.Join(dir, "monstercat", "sessions", "default.json"), e.profile.Session)
+	})
+
+	t.Run("with set, profiles and env override", func(t *testing.T) {
+		e, rest, _ := newEnvFor(t, "--profile", "staging", "config", "set", "base-url", "https://staging.example.com/api")
+		assert.NoError(t, run(context.Background(), e, rest))
+
+		e, rest, _ = newEnvFor(t, "--profile", "staging", "config", "set", "image-width", "256")
+		assert.NoError(t, run(context.Background(), e, rest))
+
+		e, rest, stdout := newEnvFor(t, "--profile", "staging", "config", "get", "image-width")
+		assert.NoError(t, run(context.Background(), e, rest))
+		assert.Equal(t, "256\n", stdout.String())
+
+		t.Setenv("MONSTERCAT_IMAGE_WIDTH", "1024")
+		e, _, _ = newEnvFor(t, "--profile", "staging")
+		assert.Equal(t, 1024, e.profile.ImageWidth)
+		assert.Equal(t, "https://staging.example.com/api", e.profile.BaseURL)
+
+		e, rest, _ = newEnvFor(t, "config", "use", "staging")
+		assert.NoError(t, run(context.Background(), e, rest))
+		e, _, _ = newEnvFor(t)
+		assert.Equal(t, "staging", e.profileName)
+
+		e, rest, _ = newEnvFor(t, "config", "unset", "image-width")
+		assert.NoError(t, run(context.Background(), e, rest))
+		cfg, err := loadConfig(e.configPath)
+		assert.NoError(t, err)
+		assert.Zero(t, cfg.Profiles["staging"].ImageWidth)
+	})
+
+	t.Run("with invalid value", func(t *testing.T) {
+		e, rest, _ := newEnvFor(t, "config", "set", "rate-limit", "fast")
+		assert.Error(t, run(context.Background(), e, rest))
+	})
+
+	t.Run("with filename template", func(t *testing.T) {
+		p := profile{FilenameTemplate: "{catalog}/{track} {title}.{ext}"}
+		track := monstercat.Track{Title: "Emoji", TrackNumber: 2, Release: monstercat.Release{CatalogID: "MCS123"}}
+		assert.Equal(t, "MCS123_2 Emoji.mp3", p.filename(track, "mp3"))
+	})
+}
+
+func Test_Completion(t *testing.T) {
+	e, stdout := newTestEnv()
+	e.seenPath = filepath.Join(t.TempDir(), "seen.json")
+
+	completeWords := func(t *testing.T, words ...string) []string {
+		stdout.Reset()
+		assert.NoError(t, run(context.Background(), e, append([]string{"__complete"}, words...)))
+		return strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	}
+
+	t.Run("with commands", func(t *testing.T) {
+		assert.Equal(t, []string{"search\tsearch the catalog", "stream-url\tprint the signed stream url of a track", "sync\tmirror an artist's tracks into a directory"}, completeWords(t, "s"))
+		assert.Equal(t, []string{"release\tshow a release and its tracks"}, completeWords(t, "--profile", "staging", "rel"))
+	})
+
+	t.Run("with seen releases and artists", func(t *testing.T) {
+		stdout.Reset()
+		assert.NoError(t, run(context.Background(), e, []string{"search", "emoji"}))
+
+		assert.Equal(t, []string{"MCS123\tEmoji - Pegboard Nerds"}, completeWords(t, "release", "MC"))
+		assert.Equal(t, []string{"pegboardnerds\tPegboard Nerds"}, completeWords(t, "artist", ""))
+		assert.Equal(t, []string{"release\tMCS123 Emoji - Pegboard Nerds"}, completeWords(t, "download", "--tags", ""))
+		assert.Equal(t, []string{"track\tEmoji"}, completeWords(t, "download", "release", ""))
+		assert.Equal(t, []string{"Pegboard Nerds"}, completeWords(t, "sync", "--artist", "Peg"))
+	})
+
+	t.Run("with flags taking values", func(t *testing.T) {
+		assert.Equal(t, []string{"MCS123\tEmoji - Pegboard Nerds"}, completeWords(t, "cover", "-width", "256", ""))
+		assert.Equal(t, []string{"track\tEmoji"}, completeWords(t, "download", "-o", "f.mp3", "release", ""))
+		assert.Equal(t, []string{"release\tMCS123 Emoji - Pegboard Nerds"}, completeWords(t, "release", "-uuid", ""))
+		assert.Equal(t, []string{""}, completeWords(t, "cover", "-width", ""))
+	})
+
+	t.Run("with flag values and scripts", func(t *testing.T) {
+		assert.Equal(t, []string{"EP"}, completeWords(t, "search", "--type", "E"))
+		assert.Equal(t, []string{"ndjson"}, completeWords(t, "search", "--format", "n"))
+		assert.Equal(t, []string{"flac"}, completeWords(t, "download", "--format", "f"))
+		assert.Equal(t, []string{"zsh"}, completeWords(t, "completion", "z"))
+
+		for _, shell := range []string{"bash", "zsh", "fish"} {
+			stdout.Reset()
+			assert.NoError(t, run(context.Background(), e, []string{"completion", shell}))
+			assert.Contains(t, stdout.String(), "monstercat __complete")
+		}
+		assert.Error(t, run(context.Background(), e, []string{"completion", "powershell"}))
+	})
+
+	t.Run("with most recent first", func(t *testing.T) {
+		seen := &seenCache{}
+		seen.add([]monstercat.Track{{ID: "a", Release: monstercat.Release{ID: "r1"}}})
+		seen.add([]monstercat.Track{{ID: "b", Release: monstercat.Release{ID: "r2"}}})
+		seen.add([]monstercat.Track{{ID: "c", Release: monstercat.Release{ID: "r1"}}})
+		assert.Equal(t, "r1", seen.Releases[0].ID)
+		assert.Equal(t, []seenTrack{{ID: "c"}, {ID: "a"}}, seen.Releases[0].Tracks)
+		assert.Equal(t, "r2", seen.Releases[1].ID)
+	})
+}
+
+// newTUITestEnv serves the catalog, a release, signed stream urls and fake audio.
+func newTUITestEnv(t *testing.T) *env {
+	e, _ := newTestEnv()
+	e.client = monstercat.NewClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := catalogResponse
+			switch {
+			case req.URL.Query().Get("noRedirect") == "true":
+				body = `{"SignedURL": "https://cdn.example.com/emoji.mp3"}`
+			case strings.Contains(req.URL.Path, "/track-stream/"):
+				body = "audio-frames"
+			case strings.Contains(req.URL.Path, "/catalog/release/"):
+				body = `{"Release": {"Id": "release", "CatalogId": "MCS123", "Title": "Emoji", "Type": "Single"}}`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     make(http.Header),
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}),
+	})
+	e.profile.OutputDir = t.TempDir()
+	return e
+}
+
+// drive feeds the messages to the model, running the requests they start until none are left.
+func drive(m *tuiModel, msgs ...tuiMsg) {
+	for len(msgs) > 0 {
+		msg := msgs[0]
+		msgs = msgs[1:]
+		for _, cmd := range m.update(msg) {
+			msgs = append(msgs, cmd(context.Background()))
+		}
+	}
+}
+
+func typeKeys(s string) []tuiMsg {
+	msgs := make([]tuiMsg, 0)
+	for _, key := range parseKeys([]byte(s)) {
+		msgs = append(msgs, keyMsg(key))
+	}
+	return msgs
+}
+
+func Test_TUI(t *testing.T) {
+	t.Run("with search, release and actions", func(t *testing.T) {
+		e := newTUITestEnv(t)
+		m := newTUIModel(e, 100, 12)
+
+		drive(m, typeKeys("emoji\r")...)
+		assert.Equal(t, focusResults, m.focus)
+		assert.Len(t, m.tracks, 1)
+
+		screen := m.view()
+		assert.Len(t, strings.Split(screen, "\r\n"), 12)
+		assert.Contains(t, screen, "search: emoji")
+		assert.Contains(t, screen, "Pegboard Nerds - Emoji")
+		assert.Contains(t, screen, " 128 Electro")
+		assert.Contains(t, screen, "4:05")
+
+		drive(m, keyMsg("enter"))
+		assert.Equal(t, focusRelease, m.focus)
+		assert.Equal(t, "MCS123", m.release.CatalogID)
+		assert.Contains(t, m.view(), "Single · MCS123")
+
+		drive(m, keyMsg("c"))
+		assert.Equal(t, "https://cdn.example.com/emoji.mp3", m.clipboard)
+		assert.Contains(t, m.view(), "copied stream url of Emoji")
+
+		drive(m, keyMsg("d"))
+		assert.False(t, m.downloading)
+		b, err := os.ReadFile(filepath.Join(e.profile.OutputDir, "Pegboard Nerds - Emoji.mp3"))
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(b), "ID3"))
+		assert.True(t, strings.HasSuffix(string(b), "audio-frames"))
+
+		drive(m, keyMsg("esc"), keyMsg("q"))
+		assert.Equal(t, focusResults, m.focus)
+		assert.True(t, m.quit)
+	})
+
+	t.Run("with stale search results ignored", func(t *testing.T) {
+		m := newTUIModel(newTUITestEnv(t), 80, 10)
+		m.query = "old"
+		stale := m.search()
+		m.query = "new"
+		drive(m, keyMsg("enter"))
+		assert.Len(t, m.tracks, 1)
+
+		m.update(searchMsg{seq: 1})
+		assert.Len(t, m.tracks, 1)
+		assert.Len(t, stale, 1)
+	})
+
+	t.Run("with small terminal", func(t *testing.T) {
+		m := newTUIModel(newTUITestEnv(t), 20, 3)
+		assert.Equal(t, "terminal too small  ", m.view())
+	})
+
+	t.Run("with keys", func(t *testing.T) {
+		keys := parseKeys([]byte("a\x1b[A\x1b[B\x1b[6~\x1b\x7f\r\tü\x03"))
+		assert.Equal(t, []string{"a", "up", "down", "pgdown", "esc", "backspace", "enter", "tab", "ü", "ctrl+c"}, keys)
+	})
+
+	t.Run("with runtime", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		m := newTUIModel(newTUITestEnv(t), 80, 10)
+		m.query = "emoji"
+		err := runTUI(context.Background(), m, strings.NewReader("\x03"), out, nil)
+		assert.NoError(t, err)
+		assert.True(t, m.quit)
+		assert.Contains(t, out.String(), "search: emoji")
+	})
+}
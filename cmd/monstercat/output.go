@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ppalone/monstercat"
+	"github.com/ppalone/monstercat/export"
+)
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// exportFlags are the --format and --columns flags of the commands that export.
+type exportFlags struct {
+	format  *string
+	columns *string
+}
+
+func addExportFlags(fs *flag.FlagSet) exportFlags {
+	return exportFlags{
+		format:  fs.String("format", "", "export as csv, json or ndjson"),
+		columns: fs.String("columns", "", "comma separated csv columns, defaults to all"),
+	}
+}
+
+// parse returns the export format and options, with an empty format when not exporting.
+func (f exportFlags) parse() (export.Format, []export.Option, error) {
+	if len(*f.format) == 0 {
+		return "", nil, nil
+	}
+
+	format, err := export.ParseFormat(*f.format)
+	if err != nil {
+		return "", nil, err
+	}
+
+	opts := []export.Option{}
+	if len(*f.columns) != 0 {
+		opts = append(opts, export.WithColumns(strings.Split(*f.columns, ",")...))
+	}
+	return format, opts, nil
+}
+
+func writeTracks(w io.Writer, tracks []monstercat.Track) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RELEASE ID\tTRACK ID\tARTISTS\tTITLE\tRELEASE\tBPM\tGENRE\tDURATION")
+	for _, t := range tracks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			t.Release.ID, t.ID, t.ArtistsTitle, t.Title, t.Release.Title, t.BPM, t.GenreSecondary, formatDuration(t.Duration))
+	}
+	return tw.Flush()
+}
+
+// writeFields writes label and value pairs as an aligned list.
+func writeFields(w io.Writer, fields [][2]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, f := range fields {
+		if len(f[1]) != 0 {
+			fmt.Fprintf(tw, "%s:\t%s\n", f[0], f[1])
+		}
+	}
+	return tw.Flush()
+}
+
+func trackFields(t monstercat.Track) [][2]string {
+	artists := make([]string, 0)
+	for _, a := range t.Artists {
+		artists = append(artists, fmt.Sprintf("%s (%s)", a.Name, a.Role))
+	}
+
+	return [][2]string{
+		{"Title", t.Title},
+		{"Artists", t.ArtistsTitle},
+		{"Credits", strings.Join(artists, ", ")},
+		{"Track ID", t.ID},
+		{"Release", t.Release.Title},
+		{"Release ID", t.Release.ID},
+		{"Catalog ID", t.Release.CatalogID},
+		{"ISRC", t.ISRC},
+		{"BPM", formatInt(t.BPM)},
+		{"Genre", strings.Trim(t.GenrePrimary+" / "+t.GenreSecondary, " /")},
+		{"Duration", formatDuration(t.Duration)},
+		{"Brand", t.Brand},
+		{"Debut", formatDate(t.DebutDate)},
+		{"Explicit", fmt.Sprint(t.Explicit)},
+		{"Creator Friendly", fmt.Sprint(t.CreatorFriendly)},
+		{"Downloadable", fmt.Sprint(t.Downloadable)},
+	}
+}
+
+func formatDuration(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ppalone/monstercat"
+)
+
+// most releases and artists kept in the seen cache.
+const maxSeen = 500
+
+// seenCache remembers the releases and artists the CLI has shown, most recent first, for completion.
+type seenCache struct {
+	Releases []seenRelease `json:"releases,omitempty"`
+	Artists  []seenArtist  `json:"artists,omitempty"`
+}
+
+type seenRelease struct {
+	ID        string      `json:"id"`
+	CatalogID string      `json:"catalogId,omitempty"`
+	Title     string      `json:"title,omitempty"`
+	Artists   string      `json:"artists,omitempty"`
+	Tracks    []seenTrack `json:"tracks,omitempty"`
+}
+
+type seenTrack struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+type seenArtist struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// seenPath returns the seen cache path in the XDG cache directory.
+func seenPath() (string, error) {
+	if dir := os.Getenv("XDG_CACHE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "monstercat", "seen.json"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".cache", "monstercat", "seen.json"), nil
+}
+
+func loadSeen(path string) (*seenCache, error) {
+	seen := &seenCache{}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return seen, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, seen); err != nil {
+		return nil, fmt.Errorf("invalid seen cache %s: %w", path, err)
+	}
+	return seen, nil
+}
+
+func saveSeen(path string, seen *seenCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(seen)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so concurrent runs never leave a torn cache.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// add moves the releases and artists of the tracks to the front of the cache.
+func (s *seenCache) add(tracks []monstercat.Track) {
+	releases := make([]seenRelease, 0)
+	releaseIndex := make(map[string]int)
+	artists := make([]seenArtist, 0)
+	artistIndex := make(map[string]bool)
+
+	for _, track := range tracks {
+		if r := track.Release; len(r.ID) != 0 {
+			i, ok := releaseIndex[r.ID]
+			if !ok {
+				i = len(releases)
+				releaseIndex[r.ID] = i
+				releases = append(releases, seenRelease{
+					ID:        r.ID,
+					CatalogID: r.CatalogID,
+					Title:     r.Title,
+					Artists:   track.ArtistsTitle,
+				})
+			}
+			releases[i].addTrack(seenTrack{ID: track.ID, Title: track.Title})
+		}
+
+		for _, a := range track.Artists {
+			if len(a.URI) != 0 && !artistIndex[a.URI] {
+				artistIndex[a.URI] = true
+				artists = append(artists, seenArtist{URI: a.URI, Name: a.Name})
+			}
+		}
+	}
+
+	for _, old := range s.Releases {
+		if i, ok := releaseIndex[old.ID]; ok {
+			for _, track := range old.Tracks {
+				releases[i].addTrack(track)
+			}
+			continue
+		}
+		releases = append(releases, old)
+	}
+
+	for _, old := range s.Artists {
+		if !artistIndex[old.URI] {
+			artists = append(artists, old)
+		}
+	}
+
+	s.Releases = releases[:min(len(releases), maxSeen)]
+	s.Artists = artists[:min(len(artists), maxSeen)]
+}
+
+func (r *seenRelease) addTrack(track seenTrack) {
+	if len(track.ID) == 0 {
+		return
+	}
+	for _, t := range r.Tracks {
+		if t.ID == track.ID {
+			return
+		}
+	}
+	r.Tracks = append(r.Tracks, track)
+}
+
+// remember adds the tracks a command showed to the seen cache. It is best effort,
+// a broken cache only costs completions, so errors are ignored.
+func (e *env) remember(tracks []monstercat.Track) {
+	if len(e.seenPath) == 0 || len(tracks) == 0 {
+		return
+	}
+
+	seen, err := loadSeen(e.seenPath)
+	if err != nil {
+		seen = &seenCache{}
+	}
+	seen.add(tracks)
+	saveSeen(e.seenPath, seen)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ppalone/monstercat"
+)
+
+// name of the manifest kept in the synced directory.
+const manifestName = ".monstercat-sync.json"
+
+// manifest records what a sync downloaded, so reruns only fetch new tracks.
+type manifest struct {
+	Tracks map[string]manifestEntry `json:"tracks"`
+}
+
+type manifestEntry struct {
+	File         string    `json:"file"`
+	Title        string    `json:"title"`
+	ArtistsTitle string    `json:"artistsTitle"`
+	Release      string    `json:"release"`
+	CatalogID    string    `json:"catalogId"`
+	DownloadedAt time.Time `json:"downloadedAt"`
+}
+
+func runSync(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "sync", "")
+	artist := fs.String("artist", "", "artist name to mirror (required)")
+	releaseType := fs.String("type", "", "only releases of this type: Single, EP or Album")
+	dir := fs.String("dir", e.profile.OutputDir, "library directory")
+	coverWidth := fs.Int("cover", e.profile.ImageWidth, "embed the cover at this width, 0 to skip")
+	dryRun := fs.Bool("dry-run", false, "report what would change without downloading")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(*artist)) == 0 {
+		fs.Usage()
+		return fmt.Errorf("sync needs --artist")
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+
+	m, err := loadManifest(*dir)
+	if err != nil {
+		return err
+	}
+
+	tracks, err := discoverTracks(ctx, e.client, *artist, monstercat.ReleaseType(*releaseType))
+	if err != nil {
+		return err
+	}
+
+	// files are named after the artists and title alone, which releases share.
+	claimed := make(map[string]string, len(m.Tracks))
+	for key, entry := range m.Tracks {
+		claimed[entry.File] = key
+	}
+
+	seen := make(map[string]bool)
+	downloaded, failed := 0, 0
+	for _, track := range tracks {
+		key := track.Release.ID + "/" + track.ID
+		seen[key] = true
+
+		if entry, ok := m.Tracks[key]; ok {
+			if entry.Title != track.Title || entry.Release != track.Release.Title {
+				fmt.Fprintf(e.stdout, "retitled  %s: %q is now %q on %q\n", entry.File, entry.Title, track.Title, track.Release.Title)
+				entry.Title, entry.Release = track.Title, track.Release.Title
+				m.Tracks[key] = entry
+			}
+			if _, err := os.Stat(filepath.Join(*dir, entry.File)); err == nil {
+				continue
+			}
+		}
+
+		file := uniqueFilename(claimed, key, e.profile.filename(track, "mp3"), track)
+		claimed[file] = key
+		if *dryRun {
+			fmt.Fprintf(e.stdout, "new       %s\n", file)
+			continue
+		}
+
+		if err := syncTrack(ctx, e.client, track, filepath.Join(*dir, file), *coverWidth); err != nil {
+			fmt.Fprintf(e.stderr, "failed    %s: %v\n", file, err)
+			failed++
+			continue
+		}
+
+		m.Tracks[key] = manifestEntry{
+			File:         file,
+			Title:        track.Title,
+			ArtistsTitle: track.ArtistsTitle,
+			Release:      track.Release.Title,
+			CatalogID:    track.Release.CatalogID,
+			DownloadedAt: time.Now().UTC(),
+		}
+		fmt.Fprintf(e.stdout, "added     %s\n", file)
+		downloaded++
+	}
+
+	for key, entry := range m.Tracks {
+		if !seen[key] {
+			fmt.Fprintf(e.stdout, "missing   %s is no longer in the catalog, kept\n", entry.File)
+		}
+	}
+
+	if !*dryRun {
+		if err := saveManifest(*dir, m); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(e.stderr, "%d tracks, %d downloaded, %d failed\n", len(tracks), downloaded, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d tracks failed to download", failed)
+	}
+	return nil
+}
+
+// discoverTracks returns every catalog track credited to the artist.
+func discoverTracks(ctx context.Context, c *monstercat.Client, artist string, releaseType monstercat.ReleaseType) ([]monstercat.Track, error) {
+	opts := []monstercat.Option{}
+	if len(releaseType) != 0 {
+		opts = append(opts, monstercat.WithReleaseType(releaseType))
+	}
+
+	tracks := make([]monstercat.Track, 0)
+	res, err := c.SearchCatalog(ctx, artist, opts...)
+	for err == nil {
+		for _, track := range res.Tracks {
+			// search is fuzzy, keep only tracks that credit the artist.
+			for _, a := range track.Artists {
+				if strings.EqualFold(a.Name, artist) || a.URI == artist {
+					tracks = append(tracks, track)
+					break
+				}
+			}
+		}
+		if !res.HasNext {
+			return tracks, nil
+		}
+		res, err = res.Next(ctx)
+	}
+	return nil, err
+}
+
+// uniqueFilename returns file, or when another track claimed it, file suffixed
+// with the catalog id of the release and then the track id.
+func uniqueFilename(claimed map[string]string, key, file string, track monstercat.Track) string {
+	ext := filepath.Ext(file)
+	base := strings.TrimSuffix(file, ext)
+
+	for _, suffix := range []string{"", track.Release.CatalogID, track.ID} {
+		candidate := file
+		if len(suffix) != 0 {
+			candidate = fmt.Sprintf("%s (%s)%s", base, suffix, ext)
+		}
+		if owner, ok := claimed[candidate]; !ok || owner == key {
+			return candidate
+		}
+	}
+	return fmt.Sprintf("%s (%s %s)%s", base, track.Release.CatalogID, track.ID, ext)
+}
+
+// syncTrack downloads the tagged track next to its final path and moves it into place.
+func syncTrack(ctx context.Context, c *monstercat.Client, track monstercat.Track, path string, coverWidth int) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	opts := []monstercat.DownloadOption{monstercat.WithTags()}
+	if coverWidth > 0 {
+		opts = append(opts, monstercat.WithCoverArt(coverWidth))
+	}
+
+	_, err = c.DownloadTrack(ctx, track, tmp, opts...)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func loadManifest(dir string) (*manifest, error) {
+	m := &manifest{Tracks: make(map[string]manifestEntry)}
+
+	b, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", manifestName, err)
+	}
+	if m.Tracks == nil {
+		m.Tracks = make(map[string]manifestEntry)
+	}
+	return m, nil
+}
+
+func saveManifest(dir string, m *manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write then rename so an interrupted sync never leaves a broken manifest.
+	tmp := filepath.Join(dir, manifestName+".tmp")
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(dir, manifestName))
+}
@@ -0,0 +1,22 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func makeRaw(fd int) (func() error, error) {
+	return nil, fmt.Errorf("raw terminal mode is not supported on this platform")
+}
+
+func disableEcho(fd int) (func() error, error) {
+	return nil, fmt.Errorf("disabling terminal echo is not supported on this platform")
+}
+
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, fmt.Errorf("terminal size is not supported on this platform")
+}
+
+func notifyResize(c chan<- os.Signal) {}
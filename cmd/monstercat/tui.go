@@ -0,0 +1,678 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ppalone/monstercat"
+)
+
+// The browse command is a small elm style program: input and finished requests
+// arrive as messages, update changes the model and returns the requests to run
+// next, and view renders the model as a full screen of text. Only the runtime
+// in runTUI touches the terminal, so the model can be tested on its own.
+
+type tuiFocus int
+
+const (
+	focusSearch tuiFocus = iota
+	focusResults
+	focusRelease
+)
+
+type tuiMsg any
+
+// tuiCmd runs a request off the ui loop and reports back with a message.
+type tuiCmd func(ctx context.Context) tuiMsg
+
+type keyMsg string
+
+type resizeMsg struct {
+	width, height int
+}
+
+type searchMsg struct {
+	seq     int
+	results monstercat.SearchCatalogResults
+	more    bool
+	err     error
+}
+
+type releaseMsg struct {
+	release monstercat.ReleaseInfo
+	err     error
+}
+
+type streamURLMsg struct {
+	track monstercat.Track
+	url   string
+	err   error
+}
+
+type downloadMsg struct {
+	track monstercat.Track
+	path  string
+	err   error
+}
+
+type tuiModel struct {
+	e             *env
+	width, height int
+	focus         tuiFocus
+
+	query   string
+	seq     int
+	page    monstercat.SearchCatalogResults
+	tracks  []monstercat.Track
+	cursor  int
+	loading bool
+
+	release       *monstercat.ReleaseInfo
+	releaseCursor int
+
+	queue       []monstercat.Track
+	downloading bool
+
+	status    string
+	clipboard string
+	quit      bool
+}
+
+func newTUIModel(e *env, width, height int) *tuiModel {
+	return &tuiModel{e: e, width: width, height: height}
+}
+
+// init returns the requests to run on start, a search when a query was given.
+func (m *tuiModel) init() []tuiCmd {
+	if len(m.query) == 0 {
+		return nil
+	}
+	return m.search()
+}
+
+func (m *tuiModel) update(msg tuiMsg) []tuiCmd {
+	switch msg := msg.(type) {
+	case keyMsg:
+		return m.updateKey(string(msg))
+	case resizeMsg:
+		m.width, m.height = msg.width, msg.height
+	case searchMsg:
+		if msg.seq != m.seq {
+			// results of a query that has been replaced since.
+			return nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.status = "search failed: " + msg.err.Error()
+			return nil
+		}
+		m.page = msg.results
+		if msg.more {
+			m.tracks = append(m.tracks, msg.results.Tracks...)
+		} else {
+			m.tracks, m.cursor = msg.results.Tracks, 0
+		}
+		m.status = fmt.Sprintf("%d of %d results", len(m.tracks), msg.results.Total)
+	case releaseMsg:
+		if msg.err != nil {
+			m.status = "loading release failed: " + msg.err.Error()
+			return nil
+		}
+		m.release, m.releaseCursor, m.focus = &msg.release, 0, focusRelease
+		m.status = ""
+	case streamURLMsg:
+		if msg.err != nil {
+			m.status = "getting stream url failed: " + msg.err.Error()
+			return nil
+		}
+		m.clipboard = msg.url
+		m.status = fmt.Sprintf("copied stream url of %s", msg.track.Title)
+	case downloadMsg:
+		m.downloading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("download of %s failed: %s", msg.track.Title, msg.err)
+		} else {
+			m.status = "saved " + msg.path
+		}
+		return m.nextDownload()
+	}
+	return nil
+}
+
+func (m *tuiModel) updateKey(key string) []tuiCmd {
+	if key == "ctrl+c" {
+		m.quit = true
+		return nil
+	}
+	m.status = ""
+
+	switch m.focus {
+	case focusSearch:
+		switch key {
+		case "enter":
+			m.focus = focusResults
+			return m.search()
+		case "backspace":
+			if _, size := utf8.DecodeLastRuneInString(m.query); size > 0 {
+				m.query = m.query[:len(m.query)-size]
+			}
+		case "esc", "down", "tab":
+			m.focus = focusResults
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				m.query += key
+			}
+		}
+		return nil
+	case focusResults:
+		switch key {
+		case "q":
+			m.quit = true
+		case "/":
+			m.focus = focusSearch
+		case "up", "k":
+			m.cursor = max(m.cursor-1, 0)
+		case "down", "j":
+			if m.cursor < len(m.tracks)-1 {
+				m.cursor++
+			} else {
+				return m.more()
+			}
+		case "pgup":
+			m.cursor = max(m.cursor-m.listRows(), 0)
+		case "pgdown":
+			m.cursor = max(min(m.cursor+m.listRows(), len(m.tracks)-1), 0)
+		case "tab", "right", "l":
+			if m.release != nil {
+				m.focus = focusRelease
+			}
+		case "enter":
+			if track, ok := m.selected(); ok {
+				m.status = "loading " + track.Release.Title
+				return []tuiCmd{m.loadRelease(track.Release.ID)}
+			}
+		case "c", "d":
+			if track, ok := m.selected(); ok {
+				return m.act(key, track)
+			}
+		}
+	case focusRelease:
+		switch key {
+		case "q":
+			m.quit = true
+		case "/":
+			m.focus = focusSearch
+		case "esc", "tab", "left", "h":
+			m.focus = focusResults
+		case "up", "k":
+			m.releaseCursor = max(m.releaseCursor-1, 0)
+		case "down", "j":
+			m.releaseCursor = min(m.releaseCursor+1, max(len(m.release.Tracks)-1, 0))
+		case "c", "d":
+			if track, ok := m.selected(); ok {
+				return m.act(key, track)
+			}
+		}
+	}
+
+	return nil
+}
+
+// selected returns the track under the cursor of the focused list.
+func (m *tuiModel) selected() (monstercat.Track, bool) {
+	if m.focus == focusRelease && m.release != nil {
+		if m.releaseCursor < len(m.release.Tracks) {
+			return m.release.Tracks[m.releaseCursor], true
+		}
+		return monstercat.Track{}, false
+	}
+	if m.cursor < len(m.tracks) {
+		return m.tracks[m.cursor], true
+	}
+	return monstercat.Track{}, false
+}
+
+func (m *tuiModel) search() []tuiCmd {
+	m.seq++
+	m.loading = true
+	m.status = "searching"
+
+	seq, e, query := m.seq, m.e, m.query
+	return []tuiCmd{func(ctx context.Context) tuiMsg {
+		res, err := e.client.SearchCatalog(ctx, query)
+		if err == nil {
+			e.remember(res.Tracks)
+		}
+		return searchMsg{seq: seq, results: res, err: err}
+	}}
+}
+
+// more loads the next page of results once the cursor hits the end of the list.
+func (m *tuiModel) more() []tuiCmd {
+	if m.loading || !m.page.HasNext {
+		return nil
+	}
+	m.loading = true
+	m.status = "loading more"
+
+	seq, e, page := m.seq, m.e, m.page
+	return []tuiCmd{func(ctx context.Context) tuiMsg {
+		res, err := page.Next(ctx)
+		if err == nil {
+			e.remember(res.Tracks)
+		}
+		return searchMsg{seq: seq, results: res, more: true, err: err}
+	}}
+}
+
+func (m *tuiModel) loadRelease(id string) tuiCmd {
+	e := m.e
+	return func(ctx context.Context) tuiMsg {
+		release, err := e.client.GetRelease(ctx, id, monstercat.WithIdType(monstercat.UUID))
+		if err == nil {
+			e.remember(release.Tracks)
+		}
+		return releaseMsg{release: release, err: err}
+	}
+}
+
+// act copies the stream url of the track or queues its download.
+func (m *tuiModel) act(key string, track monstercat.Track) []tuiCmd {
+	if key == "d" {
+		m.queue = append(m.queue, track)
+		m.status = "queued " + track.Title
+		return m.nextDownload()
+	}
+
+	e := m.e
+	return []tuiCmd{func(ctx context.Context) tuiMsg {
+		u, err := e.client.GetTrackStreamURL(ctx, track)
+		return streamURLMsg{track: track, url: u.URL, err: err}
+	}}
+}
+
+// nextDownload starts the next queued download, one at a time.
+func (m *tuiModel) nextDownload() []tuiCmd {
+	if m.downloading || len(m.queue) == 0 {
+		return nil
+	}
+	track := m.queue[0]
+	m.queue = m.queue[1:]
+	m.downloading = true
+
+	e := m.e
+	return []tuiCmd{func(ctx context.Context) tuiMsg {
+		path, err := downloadToProfile(ctx, e, track)
+		return downloadMsg{track: track, path: path, err: err}
+	}}
+}
+
+// downloadToProfile downloads the tagged track into the profile output directory.
+func downloadToProfile(ctx context.Context, e *env, track monstercat.Track) (string, error) {
+	if err := os.MkdirAll(e.profile.OutputDir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(e.profile.OutputDir, e.profile.filename(track, "mp3"))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := e.client.DownloadTrack(ctx, track, f, monstercat.WithTags()); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	return path, f.Close()
+}
+
+// listRows is the number of list rows between the search bar, the header and the status bar.
+func (m *tuiModel) listRows() int {
+	return max(m.height-3, 1)
+}
+
+const (
+	ansiReverse = "\x1b[7m"
+	ansiBold    = "\x1b[1m"
+	ansiReset   = "\x1b[0m"
+)
+
+// view renders the whole screen, one line per terminal row.
+func (m *tuiModel) view() string {
+	if m.width < 40 || m.height < 6 {
+		return fit("terminal too small", m.width)
+	}
+
+	lines := make([]string, 0, m.height)
+
+	search := " search: " + m.query
+	if m.focus == focusSearch {
+		search += "_"
+	}
+	lines = append(lines, ansiReverse+fit(search, m.width)+ansiReset)
+
+	leftWidth, rightWidth := m.width, 0
+	if m.release != nil {
+		leftWidth = m.width * 3 / 5
+		rightWidth = m.width - leftWidth - 1
+	}
+
+	rows := m.listRows()
+	left := m.viewResults(leftWidth, rows+1)
+	if m.release == nil {
+		lines = append(lines, left...)
+	} else {
+		right := m.viewRelease(rightWidth, rows+1)
+		for i := range left {
+			lines = append(lines, left[i]+"│"+right[i])
+		}
+	}
+
+	lines = append(lines, ansiReverse+fit(" "+m.statusLine(), m.width)+ansiReset)
+	return strings.Join(lines, "\r\n")
+}
+
+// viewResults renders the header and the results list as height lines of width cells.
+func (m *tuiModel) viewResults(width, height int) []string {
+	lines := make([]string, 0, height)
+
+	// artists and title take whatever the fixed bpm, genre and duration columns leave.
+	titleWidth := max(width-27, 1)
+	row := func(title, bpm, genre, duration string) string {
+		return fit(fmt.Sprintf("%s %4s %s %6s", fit(title, titleWidth), bpm, fit(genre, 14), duration), width)
+	}
+
+	lines = append(lines, ansiBold+row("TRACK", "BPM", "GENRE", "TIME")+ansiReset)
+
+	start := scrollStart(m.cursor, len(m.tracks), height-1)
+	for i := start; i < len(m.tracks) && len(lines) < height; i++ {
+		t := m.tracks[i]
+		line := row(t.ArtistsTitle+" - "+t.Title, formatInt(t.BPM), t.GenreSecondary, formatDuration(t.Duration))
+		if i == m.cursor && m.focus != focusSearch {
+			line = highlight(line, m.focus == focusResults)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(m.tracks) == 0 && len(lines) < height {
+		hint := "type a query and press enter"
+		if m.loading {
+			hint = "searching..."
+		} else if m.seq > 0 {
+			hint = "no results"
+		}
+		lines = append(lines, fit(" "+hint, width))
+	}
+
+	for len(lines) < height {
+		lines = append(lines, fit("", width))
+	}
+	return lines
+}
+
+// viewRelease renders the release details and its tracks as height lines of width cells.
+func (m *tuiModel) viewRelease(width, height int) []string {
+	r := m.release
+	lines := []string{
+		ansiBold + fit(" "+r.Title, width) + ansiReset,
+		fit(" "+joinNonEmpty(" · ", r.Type, r.CatalogID, formatDate(r.ReleaseDate)), width),
+		fit("", width),
+	}
+
+	start := scrollStart(m.releaseCursor, len(r.Tracks), height-len(lines))
+	for i := start; i < len(r.Tracks) && len(lines) < height; i++ {
+		t := r.Tracks[i]
+		number := formatInt(t.TrackNumber)
+		if len(number) == 0 {
+			number = formatInt(i + 1)
+		}
+		title := fmt.Sprintf(" %2s. %s", number, t.Title)
+		line := fit(fmt.Sprintf("%s %6s", fit(title, max(width-7, 1)), formatDuration(t.Duration)), width)
+		if i == m.releaseCursor {
+			line = highlight(line, m.focus == focusRelease)
+		}
+		lines = append(lines, line)
+	}
+
+	for len(lines) < height {
+		lines = append(lines, fit("", width))
+	}
+	return lines[:height]
+}
+
+func (m *tuiModel) statusLine() string {
+	status := m.status
+	if len(status) == 0 {
+		switch m.focus {
+		case focusSearch:
+			status = "enter search · esc results · ctrl+c quit"
+		case focusResults:
+			status = "enter release · c copy stream url · d download · / search · q quit"
+		case focusRelease:
+			status = "c copy stream url · d download · esc results · q quit"
+		}
+	}
+
+	if pending := len(m.queue); m.downloading || pending > 0 {
+		if m.downloading {
+			pending++
+		}
+		status += fmt.Sprintf(" · %d downloading", pending)
+	}
+	return status
+}
+
+// highlight marks the selected row, dimmer when its list is not focused.
+func highlight(line string, focused bool) string {
+	if focused {
+		return ansiReverse + line + ansiReset
+	}
+	return ansiBold + line + ansiReset
+}
+
+// scrollStart returns the first visible row so the cursor stays in the middle of the list.
+func scrollStart(cursor, total, rows int) int {
+	if rows <= 0 || total <= rows {
+		return 0
+	}
+	return max(min(cursor-rows/2, total-rows), 0)
+}
+
+// fit pads or truncates s to exactly width cells.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// parseKeys splits raw terminal input into key names. Printable input maps to
+// itself, control keys and escape sequences to names such as "enter" or "up".
+func parseKeys(b []byte) []string {
+	keys := make([]string, 0)
+	for len(b) > 0 {
+		switch c := b[0]; {
+		case c == 0x1b && len(b) > 2 && (b[1] == '[' || b[1] == 'O'):
+			// CSI or SS3 sequence: parameters then a final byte.
+			end := 2
+			for end < len(b) && (b[end] < 0x40 || b[end] > 0x7E) {
+				end++
+			}
+			if end == len(b) {
+				return keys
+			}
+			if key, ok := escapeKeys[string(b[2:end+1])]; ok {
+				keys = append(keys, key)
+			}
+			b = b[end+1:]
+		case c == 0x1b:
+			keys = append(keys, "esc")
+			b = b[1:]
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+			b = b[1:]
+		case c == 0x7F || c == 0x08:
+			keys = append(keys, "backspace")
+			b = b[1:]
+		case c == '\t':
+			keys = append(keys, "tab")
+			b = b[1:]
+		case c == 0x03:
+			keys = append(keys, "ctrl+c")
+			b = b[1:]
+		case c < 0x20:
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r != utf8.RuneError {
+				keys = append(keys, string(r))
+			}
+			b = b[size:]
+		}
+	}
+	return keys
+}
+
+var escapeKeys = map[string]string{
+	"A":  "up",
+	"B":  "down",
+	"C":  "right",
+	"D":  "left",
+	"H":  "home",
+	"F":  "end",
+	"5~": "pgup",
+	"6~": "pgdown",
+	"3~": "delete",
+}
+
+// runTUI runs the model until it quits, reading keys from in and drawing to out.
+// Messages from events, such as resizes, are fed to the model as they come.
+func runTUI(ctx context.Context, m *tuiModel, in io.Reader, out io.Writer, events <-chan tuiMsg) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	msgs := make(chan tuiMsg)
+	send := func(msg tuiMsg) {
+		select {
+		case msgs <- msg:
+		case <-ctx.Done():
+		}
+	}
+	start := func(cmds []tuiCmd) {
+		for _, cmd := range cmds {
+			go func(cmd tuiCmd) { send(cmd(ctx)) }(cmd)
+		}
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			for _, key := range parseKeys(buf[:n]) {
+				send(keyMsg(key))
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+		}
+	}()
+
+	start(m.init())
+	for {
+		fmt.Fprint(out, "\x1b[H"+m.view())
+		if len(m.clipboard) != 0 {
+			// OSC 52 asks the terminal to set the clipboard.
+			fmt.Fprintf(out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(m.clipboard)))
+			m.clipboard = ""
+		}
+
+		var msg tuiMsg
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-readErr:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case msg = <-events:
+		case msg = <-msgs:
+		}
+
+		if _, ok := msg.(resizeMsg); ok {
+			fmt.Fprint(out, "\x1b[2J")
+		}
+
+		start(m.update(msg))
+		if m.quit {
+			return nil
+		}
+	}
+}
+
+func runBrowse(ctx context.Context, e *env, args []string) error {
+	fs := newFlagSet(e, "browse", "[query]")
+	query, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	fd := int(os.Stdin.Fd())
+	width, height, err := terminalSize(fd)
+	if err != nil {
+		return fmt.Errorf("browse needs a terminal: %w", err)
+	}
+
+	restore, err := makeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("browse needs a terminal: %w", err)
+	}
+	defer restore()
+
+	// switch to the alternate screen and hide the cursor while browsing.
+	fmt.Fprint(e.stdout, "\x1b[?1049h\x1b[?25l\x1b[2J")
+	defer fmt.Fprint(e.stdout, "\x1b[?25h\x1b[?1049l")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+	defer signal.Stop(resized)
+
+	events := make(chan tuiMsg)
+	go func() {
+		for {
+			select {
+			case <-resized:
+			case <-ctx.Done():
+				return
+			}
+			if w, h, err := terminalSize(fd); err == nil {
+				select {
+				case events <- resizeMsg{w, h}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	m := newTUIModel(e, width, height)
+	m.query = strings.Join(query, " ")
+	return runTUI(ctx, m, os.Stdin, e.stdout, events)
+}
@@ -0,0 +1,165 @@
+package monstercat
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// diskCache is a size bounded, least recently used cache of files in a directory.
+type diskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu    sync.Mutex
+	size  int64
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type diskCacheEntry struct {
+	name        string
+	size        int64
+	contentType string // empty for files picked up from a previous run
+}
+
+func newDiskCache(dir string, maxBytes int64) (*diskCache, error) {
+	if maxBytes < 1 {
+		return nil, fmt.Errorf("cache size must be positive")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	d := &diskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+
+	// pick up files from a previous run, least recently modified first.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]os.FileInfo, 0)
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || len(entry.Name()) != sha256.Size*2 {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+
+	for _, info := range files {
+		d.items[info.Name()] = d.ll.PushFront(&diskCacheEntry{name: info.Name(), size: info.Size()})
+		d.size += info.Size()
+	}
+	d.evict()
+
+	return d, nil
+}
+
+// open returns the cached file for key with its content type and marks it as recently used.
+func (d *diskCache) open(key string) (*os.File, string, bool) {
+	name := diskCacheName(key)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, ok := d.items[name]
+	if !ok {
+		return nil, "", false
+	}
+
+	f, err := os.Open(filepath.Join(d.dir, name))
+	if err != nil {
+		d.remove(e)
+		return nil, "", false
+	}
+
+	d.ll.MoveToFront(e)
+	return f, e.Value.(*diskCacheEntry).contentType, true
+}
+
+// put stores src of the content type under key. It fails without caching anything when src is larger than the cache.
+func (d *diskCache) put(key string, src io.Reader, contentType string) error {
+	tmp, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, io.LimitReader(src, d.maxBytes+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if n > d.maxBytes {
+		return errTooLargeForCache
+	}
+
+	name := diskCacheName(key)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), filepath.Join(d.dir, name)); err != nil {
+		return err
+	}
+
+	if e, ok := d.items[name]; ok {
+		d.size -= e.Value.(*diskCacheEntry).size
+		d.ll.Remove(e)
+	}
+	d.items[name] = d.ll.PushFront(&diskCacheEntry{name: name, size: n, contentType: contentType})
+	d.size += n
+	d.evict()
+
+	return nil
+}
+
+var errTooLargeForCache = fmt.Errorf("file is larger than the cache")
+
+func (d *diskCache) evict() {
+	for d.size > d.maxBytes {
+		e := d.ll.Back()
+		if e == nil {
+			return
+		}
+		d.remove(e)
+	}
+}
+
+func (d *diskCache) remove(e *list.Element) {
+	entry := e.Value.(*diskCacheEntry)
+	d.ll.Remove(e)
+	delete(d.items, entry.name)
+	d.size -= entry.size
+
+	// open handles keep reading the removed file.
+	os.Remove(filepath.Join(d.dir, entry.name))
+}
+
+func diskCacheName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
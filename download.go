@@ -0,0 +1,106 @@
+package monstercat
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type DownloadFormat string
+
+// download formats.
+const (
+	DownloadMP3  DownloadFormat = "mp3_320"
+	DownloadFLAC DownloadFormat = "flac"
+	DownloadWAV  DownloadFormat = "wav"
+)
+
+func (f DownloadFormat) String() string {
+	return string(f)
+}
+
+func isDownloadFormatAllowed(format DownloadFormat) bool {
+	allowedFormats := []DownloadFormat{DownloadMP3, DownloadFLAC, DownloadWAV}
+	for _, f := range allowedFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// DownloadTrack writes the track stream to w, optionally tagging it with the track metadata.
+func (c *Client) DownloadTrack(ctx context.Context, track Track, w io.Writer, options ...DownloadOption) (int64, error) {
+	opts := newDownloadOpts()
+	for _, option := range options {
+		option(opts)
+	}
+
+	stream, err := c.GetTrackStream(ctx, track)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.Close()
+
+	if !opts.tags {
+		return io.Copy(w, stream)
+	}
+
+	tag := NewID3Tag(track)
+	if opts.coverArt {
+		cover, err := c.GetCoverImage(ctx, track.Release, WithWidth(opts.coverWidth), WithEncoding(JPEG))
+		if err != nil {
+			return 0, fmt.Errorf("error while getting cover art for track: %w", err)
+		}
+		tag.Cover = cover.Data
+		tag.CoverMIME = cover.MIMEType
+	}
+
+	return WriteTaggedMP3(w, stream, tag)
+}
+
+// DownloadTrackFile returns the track file in the provided format. It needs a
+// signed in session with download access, and fails with ErrUnauthorized
+// otherwise or ErrNotDownloadable when the track has no downloads.
+func (c *Client) DownloadTrackFile(ctx context.Context, track Track, format DownloadFormat) (io.ReadCloser, error) {
+	if len(track.ID) == 0 {
+		return nil, fmt.Errorf("track id is empty for track")
+	}
+
+	if len(track.Release.ID) == 0 {
+		return nil, fmt.Errorf("release id is empty for track")
+	}
+
+	if !isDownloadFormatAllowed(format) {
+		return nil, fmt.Errorf("invalid download format")
+	}
+
+	if !track.Downloadable {
+		return nil, ErrNotDownloadable
+	}
+
+	params := make(map[string]string)
+	params["format"] = format.String()
+
+	req, err := c.makeRequest(ctx, fmt.Sprintf("release/%s/track-download/%s", track.Release.ID, track.ID), params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return newStreamBody(ctx, resp.Body), nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		resp.Body.Close()
+		return nil, ErrUnauthorized
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("invalid track")
+	}
+}
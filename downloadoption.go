@@ -0,0 +1,33 @@
+package monstercat
+
+type downloadOpts struct {
+	tags       bool
+	coverArt   bool
+	coverWidth int
+}
+
+type DownloadOption func(o *downloadOpts)
+
+func newDownloadOpts() *downloadOpts {
+	return &downloadOpts{
+		tags:       false, // default is to keep the file as served
+		coverArt:   false,
+		coverWidth: 600,
+	}
+}
+
+// WithTags writes track metadata into an ID3v2.4 tag.
+func WithTags() DownloadOption {
+	return func(o *downloadOpts) {
+		o.tags = true
+	}
+}
+
+// WithCoverArt embeds the release cover, resized to the provided width, into the ID3v2.4 tag.
+func WithCoverArt(width int) DownloadOption {
+	return func(o *downloadOpts) {
+		o.tags = true
+		o.coverArt = true
+		o.coverWidth = width
+	}
+}
@@ -0,0 +1,38 @@
+package monstercat
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnauthorized is returned when a call needs a signed in session, or one with more access.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrNotDownloadable is returned when a track cannot be downloaded as a file.
+	ErrNotDownloadable = errors.New("track is not downloadable")
+
+	// ErrTwoFactorRequired is matched by the TwoFactorError returned from Login.
+	ErrTwoFactorRequired = errors.New("two factor authentication required")
+)
+
+// Two Factor Error.
+//
+// Returned by Login when the account needs a second factor, complete the sign in with VerifyTwoFactor.
+type TwoFactorError struct {
+	Method TwoFactorMethod
+}
+
+func (e *TwoFactorError) Error() string {
+	return fmt.Sprintf("%s via %s", ErrTwoFactorRequired, e.Method)
+}
+
+func (e *TwoFactorError) Is(target error) bool {
+	return target == ErrTwoFactorRequired
+}
+
+// Error API Response.
+type errorAPIResponse struct {
+	Name    string `json:"Name"`
+	Message string `json:"Message"`
+}
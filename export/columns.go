@@ -0,0 +1,88 @@
+package export
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ppalone/monstercat"
+)
+
+// Column is a named CSV column and how to compute its value from a record.
+type Column[T any] struct {
+	Name  string
+	Value func(T) string
+}
+
+// separator of the values flattened into one CSV cell, such as the artists of a track.
+const listSeparator = "; "
+
+// TrackColumns are the CSV columns available for tracks, in their default order.
+var TrackColumns = []Column[monstercat.Track]{
+	{"track_id", func(t monstercat.Track) string { return t.ID }},
+	{"title", func(t monstercat.Track) string { return t.Title }},
+	{"artists", func(t monstercat.Track) string { return t.ArtistsTitle }},
+	{"artist_names", func(t monstercat.Track) string {
+		return joinArtists(t.Artists, func(a monstercat.Artist) string { return a.Name })
+	}},
+	{"artist_uris", func(t monstercat.Track) string {
+		return joinArtists(t.Artists, func(a monstercat.Artist) string { return a.URI })
+	}},
+	{"artist_roles", func(t monstercat.Track) string {
+		return joinArtists(t.Artists, func(a monstercat.Artist) string { return a.Role })
+	}},
+	{"artist_ids", func(t monstercat.Track) string {
+		return joinArtists(t.Artists, func(a monstercat.Artist) string { return a.ID })
+	}},
+	{"release_id", func(t monstercat.Track) string { return t.Release.ID }},
+	{"catalog_id", func(t monstercat.Track) string { return t.Release.CatalogID }},
+	{"release_title", func(t monstercat.Track) string { return t.Release.Title }},
+	{"release_type", func(t monstercat.Track) string { return t.Release.Type }},
+	{"release_date", func(t monstercat.Track) string { return formatDate(t.Release.ReleaseDate) }},
+	{"track_number", func(t monstercat.Track) string { return formatInt(t.TrackNumber) }},
+	{"isrc", func(t monstercat.Track) string { return t.ISRC }},
+	{"bpm", func(t monstercat.Track) string { return formatInt(t.BPM) }},
+	{"duration", func(t monstercat.Track) string { return formatInt(t.Duration) }},
+	{"genre_primary", func(t monstercat.Track) string { return t.GenrePrimary }},
+	{"genre_secondary", func(t monstercat.Track) string { return t.GenreSecondary }},
+	{"brand", func(t monstercat.Track) string { return t.Brand }},
+	{"debut_date", func(t monstercat.Track) string { return formatDate(t.DebutDate) }},
+	{"explicit", func(t monstercat.Track) string { return strconv.FormatBool(t.Explicit) }},
+	{"downloadable", func(t monstercat.Track) string { return strconv.FormatBool(t.Downloadable) }},
+	{"creator_friendly", func(t monstercat.Track) string { return strconv.FormatBool(t.CreatorFriendly) }},
+}
+
+// ArtistColumns are the CSV columns available for artists, in their default order.
+var ArtistColumns = []Column[monstercat.Artist]{
+	{"artist_id", func(a monstercat.Artist) string { return a.ID }},
+	{"uri", func(a monstercat.Artist) string { return a.URI }},
+	{"name", func(a monstercat.Artist) string { return a.Name }},
+	{"role", func(a monstercat.Artist) string { return a.Role }},
+	{"public", func(a monstercat.Artist) string { return strconv.FormatBool(a.Public) }},
+	{"profile_image_url", func(a monstercat.Artist) string { return a.ProfileImageURL }},
+	{"banner_image_url", func(a monstercat.Artist) string { return a.BannerImageURL }},
+}
+
+// joinArtists flattens one field of every artist into a single cell, keeping
+// empty values so the lists of different fields stay aligned.
+func joinArtists(artists []monstercat.Artist, field func(monstercat.Artist) string) string {
+	values := make([]string, 0, len(artists))
+	for _, a := range artists {
+		values = append(values, field(a))
+	}
+	return strings.Join(values, listSeparator)
+}
+
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
@@ -0,0 +1,270 @@
+// Package export writes catalog data as CSV, pretty JSON or NDJSON, and tracks
+// as M3U8, XSPF or PLS playlists.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ppalone/monstercat"
+)
+
+// Format.
+type Format string
+
+// export formats.
+const (
+	CSV    Format = "csv"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{CSV, JSON, NDJSON}
+
+// ParseFormat returns the format with the provided name.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export format %q, one of: csv, json, ndjson", name)
+}
+
+// Option.
+type Option func(*options)
+
+type options struct {
+	columns []string
+	title   string
+}
+
+func newOptions() *options {
+	return &options{}
+}
+
+// WithColumns picks the CSV columns by name and their order. It defaults to every column.
+func WithColumns(names ...string) Option {
+	return func(o *options) {
+		o.columns = names
+	}
+}
+
+// Track Encoder.
+//
+// Writes tracks as they come, so results can be exported page by page without
+// holding them all. CSV writes a header and a row per track, JSON a pretty
+// array and NDJSON a line per track.
+type TrackEncoder struct {
+	enc *encoder[monstercat.Track]
+}
+
+// NewTrackEncoder returns an encoder writing tracks to w in the provided format.
+func NewTrackEncoder(w io.Writer, format Format, opts ...Option) (*TrackEncoder, error) {
+	enc, err := newEncoder(w, format, TrackColumns, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &TrackEncoder{enc: enc}, nil
+}
+
+// Encode writes the tracks.
+func (e *TrackEncoder) Encode(tracks ...monstercat.Track) error {
+	return e.enc.encode(tracks...)
+}
+
+// Close finishes the output, such as the end of the JSON array. It does not close the writer.
+func (e *TrackEncoder) Close() error {
+	return e.enc.close()
+}
+
+// WriteTracks writes the tracks to w in the provided format.
+func WriteTracks(w io.Writer, format Format, tracks []monstercat.Track, opts ...Option) error {
+	enc, err := NewTrackEncoder(w, format, opts...)
+	if err != nil {
+		return err
+	}
+	if err := enc.Encode(tracks...); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// WriteRelease writes the release to w in the provided format. JSON writes the
+// release with its tracks nested, CSV and NDJSON write a record per track, each
+// of which carries the release.
+func WriteRelease(w io.Writer, format Format, release monstercat.ReleaseInfo, opts ...Option) error {
+	if f, err := ParseFormat(string(format)); err == nil && f == JSON {
+		return writeIndented(w, release)
+	}
+	return WriteTracks(w, format, release.Tracks, opts...)
+}
+
+// WriteArtists writes the artists to w in the provided format.
+func WriteArtists(w io.Writer, format Format, artists []monstercat.Artist, opts ...Option) error {
+	enc, err := newEncoder(w, format, ArtistColumns, opts)
+	if err != nil {
+		return err
+	}
+	if err := enc.encode(artists...); err != nil {
+		return err
+	}
+	return enc.close()
+}
+
+// encoder writes records of one type in any of the formats.
+type encoder[T any] struct {
+	w       io.Writer
+	format  Format
+	columns []Column[T]
+	csv     *csv.Writer
+	count   int
+	closed  bool
+}
+
+func newEncoder[T any](w io.Writer, format Format, available []Column[T], opts []Option) (*encoder[T], error) {
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	format, err := ParseFormat(string(format))
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := selectColumns(available, o.columns)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := &encoder[T]{w: w, format: format, columns: columns}
+	if format == CSV {
+		enc.csv = csv.NewWriter(w)
+	}
+	return enc, nil
+}
+
+func selectColumns[T any](available []Column[T], names []string) ([]Column[T], error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	columns := make([]Column[T], 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, c := range available {
+			if c.Name == name {
+				columns = append(columns, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	return columns, nil
+}
+
+func (e *encoder[T]) encode(records ...T) error {
+	if e.closed {
+		return fmt.Errorf("encoder is closed")
+	}
+
+	for _, record := range records {
+		var err error
+		switch e.format {
+		case CSV:
+			err = e.writeCSV(record)
+		case JSON:
+			err = e.writeJSON(record)
+		case NDJSON:
+			err = json.NewEncoder(e.w).Encode(record)
+		}
+		if err != nil {
+			return err
+		}
+		e.count++
+	}
+
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+	return nil
+}
+
+func (e *encoder[T]) close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	switch e.format {
+	case CSV:
+		if e.count == 0 {
+			if err := e.writeHeader(); err != nil {
+				return err
+			}
+		}
+		e.csv.Flush()
+		return e.csv.Error()
+	case JSON:
+		end := "\n]\n"
+		if e.count == 0 {
+			end = "[]\n"
+		}
+		_, err := io.WriteString(e.w, end)
+		return err
+	}
+	return nil
+}
+
+func (e *encoder[T]) writeHeader() error {
+	header := make([]string, 0, len(e.columns))
+	for _, c := range e.columns {
+		header = append(header, c.Name)
+	}
+	return e.csv.Write(header)
+}
+
+func (e *encoder[T]) writeCSV(record T) error {
+	if e.count == 0 {
+		if err := e.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	row := make([]string, 0, len(e.columns))
+	for _, c := range e.columns {
+		row = append(row, c.Value(record))
+	}
+	return e.csv.Write(row)
+}
+
+// writeJSON writes the record as the next element of a pretty array.
+func (e *encoder[T]) writeJSON(record T) error {
+	b, err := json.MarshalIndent(record, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n  "
+	if e.count == 0 {
+		sep = "[\n  "
+	}
+	_, err = io.WriteString(e.w, sep+string(b))
+	return err
+}
+
+func writeIndented(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
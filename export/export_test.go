@@ -0,0 +1,275 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ppalone/monstercat"
+	"github.com/ppalone/monstercat/export"
+	"github.com/stretchr/testify/assert"
+)
+
+var testTracks = []monstercat.Track{
+	{
+		ID:           "track-1",
+		Title:        "Emoji",
+		ArtistsTitle: "Pegboard Nerds & Tokyo Machine",
+		BPM:          128,
+		Duration:     245,
+		Artists: []monstercat.Artist{
+			{Name: "Pegboard Nerds", URI: "pegboardnerds", Role: "Primary"},
+			{Name: "Tokyo Machine", URI: "tokyomachine", Role: "Primary"},
+		},
+		Release: monstercat.Release{
+			ID:          "release",
+			CatalogID:   "MCS123",
+			Title:       "Emoji",
+			ReleaseDate: time.Date(2019, 4, 2, 0, 0, 0, 0, time.UTC),
+		},
+	},
+	{ID: "track-2", Title: "Emoji, \"VIP\"", Release: monstercat.Release{ID: "release"}},
+}
+
+func Test_WriteTracks(t *testing.T) {
+	t.Run("with csv columns and flattened artists", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := export.WriteTracks(buf, export.CSV, testTracks, export.WithColumns("title", "artist_uris", "release_date", "bpm"))
+		assert.NoError(t, err)
+
+		rows, err := csv.NewReader(buf).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"title", "artist_uris", "release_date", "bpm"},
+			{"Emoji", "pegboardnerds; tokyomachine", "2019-04-02", "128"},
+			{"Emoji, \"VIP\"", "", "", ""},
+		}, rows)
+	})
+
+	t.Run("with default csv columns", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WriteTracks(buf, export.CSV, testTracks))
+		header, _, _ := strings.Cut(buf.String(), "\n")
+		assert.Len(t, strings.Split(header, ","), len(export.TrackColumns))
+	})
+
+	t.Run("with unknown column", func(t *testing.T) {
+		err := export.WriteTracks(new(bytes.Buffer), export.CSV, testTracks, export.WithColumns("mood"))
+		assert.EqualError(t, err, `unknown column "mood"`)
+	})
+
+	t.Run("with pretty json", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WriteTracks(buf, export.JSON, testTracks))
+		assert.True(t, strings.HasPrefix(buf.String(), "[\n  {\n    "))
+
+		var tracks []monstercat.Track
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &tracks))
+		assert.Equal(t, testTracks, tracks)
+	})
+
+	t.Run("with empty json", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WriteTracks(buf, export.JSON, nil))
+		assert.Equal(t, "[]\n", buf.String())
+	})
+
+	t.Run("with ndjson", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WriteTracks(buf, export.NDJSON, testTracks))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		assert.Len(t, lines, 2)
+		var track monstercat.Track
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &track))
+		assert.Equal(t, testTracks[1], track)
+	})
+
+	t.Run("with unsupported format", func(t *testing.T) {
+		assert.Error(t, export.WriteTracks(new(bytes.Buffer), "xml", testTracks))
+	})
+
+	t.Run("with upper case format", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WriteTracks(buf, export.Format("CSV"), testTracks, export.WithColumns("track_id")))
+		assert.Equal(t, "track_id\ntrack-1\ntrack-2\n", buf.String())
+
+		buf.Reset()
+		assert.NoError(t, export.WriteRelease(buf, export.Format("Json"), monstercat.ReleaseInfo{ID: "release"}))
+		assert.Equal(t, "{\n  \"id\": \"release\"\n}\n", buf.String())
+	})
+}
+
+func Test_TrackEncoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	enc, err := export.NewTrackEncoder(buf, export.CSV, export.WithColumns("track_id"))
+	assert.NoError(t, err)
+
+	// the header is only written once across pages.
+	assert.NoError(t, enc.Encode(testTracks[0]))
+	assert.NoError(t, enc.Encode(testTracks[1]))
+	assert.NoError(t, enc.Close())
+	assert.Equal(t, "track_id\ntrack-1\ntrack-2\n", buf.String())
+
+	assert.Error(t, enc.Encode(testTracks[0]))
+}
+
+func Test_WriteRelease(t *testing.T) {
+	release := monstercat.ReleaseInfo{ID: "release", CatalogID: "MCS123", Title: "Emoji", Tracks: testTracks}
+
+	t.Run("with json", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WriteRelease(buf, export.JSON, release))
+
+		var got monstercat.ReleaseInfo
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+		assert.Equal(t, release, got)
+	})
+
+	t.Run("with csv", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WriteRelease(buf, export.CSV, release, export.WithColumns("catalog_id", "track_id")))
+		assert.Equal(t, "catalog_id,track_id\nMCS123,track-1\n,track-2\n", buf.String())
+	})
+}
+
+func Test_WriteArtists(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := export.WriteArtists(buf, export.CSV, testTracks[0].Artists, export.WithColumns("uri", "name"))
+	assert.NoError(t, err)
+	assert.Equal(t, "uri,name\npegboardnerds,Pegboard Nerds\ntokyomachine,Tokyo Machine\n", buf.String())
+}
+
+func Test_ParseFormat(t *testing.T) {
+	f, err := export.ParseFormat("NDJSON")
+	assert.NoError(t, err)
+	assert.Equal(t, export.NDJSON, f)
+
+	_, err = export.ParseFormat("xlsx")
+	assert.Error(t, err)
+}
+
+func Test_WritePlaylist(t *testing.T) {
+	locate := export.ProxyLocator("http://localhost:8080/")
+
+	t.Run("with m3u8", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := export.WritePlaylist(context.Background(), buf, export.M3U8, testTracks, locate, export.WithTitle("Emoji\nVIPs"))
+		assert.NoError(t, err)
+		assert.Equal(t, "#EXTM3U\n#PLAYLIST:Emoji VIPs\n"+
+			"#EXTINF:245,Pegboard Nerds & Tokyo Machine - Emoji\nhttp://localhost:8080/tracks/release/track-1\n"+
+			"#EXTINF:-1,Emoji, \"VIP\"\nhttp://localhost:8080/tracks/release/track-2\n", buf.String())
+	})
+
+	t.Run("with pls", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := export.WritePlaylist(context.Background(), buf, export.PLS, testTracks[:1], locate)
+		assert.NoError(t, err)
+		assert.Equal(t, "[playlist]\nFile1=http://localhost:8080/tracks/release/track-1\n"+
+			"Title1=Pegboard Nerds & Tokyo Machine - Emoji\nLength1=245\nNumberOfEntries=1\nVersion=2\n", buf.String())
+	})
+
+	t.Run("with xspf and file paths", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "My Music")
+		files := export.FileLocator(dir, func(track monstercat.Track) string { return track.ID + ".mp3" })
+
+		buf := new(bytes.Buffer)
+		release := monstercat.ReleaseInfo{Title: "Emoji & Friends", Tracks: testTracks}
+		assert.NoError(t, export.WriteReleasePlaylist(context.Background(), buf, export.XSPF, release, files))
+
+		playlist := struct {
+			Title  string `xml:"title"`
+			Tracks []struct {
+				Location string `xml:"location"`
+				Title    string `xml:"title"`
+				Creator  string `xml:"creator"`
+				Duration int    `xml:"duration"`
+			} `xml:"trackList>track"`
+		}{}
+		assert.NoError(t, xml.Unmarshal(buf.Bytes(), &playlist))
+		assert.Equal(t, "Emoji & Friends", playlist.Title)
+		assert.Len(t, playlist.Tracks, 2)
+		assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(strings.ReplaceAll(dir, " ", "%20"), "track-1.mp3")), playlist.Tracks[0].Location)
+		assert.Equal(t, "Pegboard Nerds & Tokyo Machine", playlist.Tracks[0].Creator)
+		assert.Equal(t, 245000, playlist.Tracks[0].Duration)
+	})
+
+	t.Run("with empty playlist", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WritePlaylist(context.Background(), buf, export.XSPF, nil, locate))
+		assert.NoError(t, xml.Unmarshal(buf.Bytes(), new(struct{})))
+	})
+
+	t.Run("with signed stream urls", func(t *testing.T) {
+		c := monstercat.NewClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     make(http.Header),
+					Body:       io.NopCloser(strings.NewReader(`{"SignedURL": "https://cdn.example.com/emoji.mp3?X-Amz-Expires=60"}`)),
+					Request:    req,
+				}, nil
+			}),
+		})
+
+		buf := new(bytes.Buffer)
+		err := export.WritePlaylist(context.Background(), buf, export.M3U8, testTracks[:1], export.StreamURLLocator(c))
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "\nhttps://cdn.example.com/emoji.mp3?X-Amz-Expires=60\n")
+	})
+
+	t.Run("with search pages", func(t *testing.T) {
+		pages := []string{
+			`{"Limit": 1, "Offset": 0, "Total": 2, "Data": [{"Id": "track-1", "Title": "Emoji", "Release": {"Id": "release"}}]}`,
+			`{"Limit": 1, "Offset": 1, "Total": 2, "Data": [{"Id": "track-2", "Title": "Emoji VIP", "Release": {"Id": "release"}}]}`,
+		}
+		c := monstercat.NewClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				page := pages[0]
+				pages = pages[1:]
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     make(http.Header),
+					Body:       io.NopCloser(strings.NewReader(page)),
+					Request:    req,
+				}, nil
+			}),
+		})
+
+		res, err := c.SearchCatalog(context.Background(), "emoji", monstercat.WithLimit(1))
+		assert.NoError(t, err)
+
+		buf := new(bytes.Buffer)
+		assert.NoError(t, export.WriteSearchPlaylist(context.Background(), buf, export.PLS, res, locate))
+		assert.Contains(t, buf.String(), "File2=http://localhost:8080/tracks/release/track-2\n")
+		assert.Contains(t, buf.String(), "NumberOfEntries=2\n")
+	})
+
+	t.Run("with upper case format", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		err := export.WritePlaylist(context.Background(), buf, export.PlaylistFormat("M3U8"), testTracks[:1], locate)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(buf.String(), "#EXTM3U\n#EXTINF:245,"))
+	})
+
+	t.Run("with invalid format and failing locator", func(t *testing.T) {
+		assert.Error(t, export.WritePlaylist(context.Background(), new(bytes.Buffer), "wpl", testTracks, locate))
+
+		err := export.WritePlaylist(context.Background(), new(bytes.Buffer), export.M3U8, []monstercat.Track{{ID: "track"}}, locate)
+		assert.ErrorContains(t, err, "error locating track track")
+	})
+}
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
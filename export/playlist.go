@@ -0,0 +1,307 @@
+package export
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ppalone/monstercat"
+)
+
+// Playlist Format.
+type PlaylistFormat string
+
+// playlist formats.
+const (
+	M3U8 PlaylistFormat = "m3u8"
+	XSPF PlaylistFormat = "xspf"
+	PLS  PlaylistFormat = "pls"
+)
+
+// ParsePlaylistFormat returns the playlist format with the provided name.
+func ParsePlaylistFormat(name string) (PlaylistFormat, error) {
+	for _, f := range []PlaylistFormat{M3U8, XSPF, PLS} {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported playlist format %q, one of: m3u8, xspf, pls", name)
+}
+
+// WithTitle sets the title of the playlist.
+func WithTitle(title string) Option {
+	return func(o *options) {
+		o.title = title
+	}
+}
+
+// Locator returns where a player finds the track, a url or a file path.
+type Locator func(ctx context.Context, track monstercat.Track) (string, error)
+
+// StreamURLLocator points at the signed stream urls of the tracks. They
+// expire, so the playlist is only good for a while.
+func StreamURLLocator(c *monstercat.Client) Locator {
+	return func(ctx context.Context, track monstercat.Track) (string, error) {
+		u, err := c.GetTrackStreamURL(ctx, track)
+		if err != nil {
+			return "", err
+		}
+		return u.URL, nil
+	}
+}
+
+// ProxyLocator points at a monstercat.StreamProxy served at baseURL.
+func ProxyLocator(baseURL string) Locator {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return func(ctx context.Context, track monstercat.Track) (string, error) {
+		if len(track.Release.ID) == 0 || len(track.ID) == 0 {
+			return "", fmt.Errorf("release id and track id are required for track")
+		}
+		return fmt.Sprintf("%s/tracks/%s/%s", baseURL, url.PathEscape(track.Release.ID), url.PathEscape(track.ID)), nil
+	}
+}
+
+// FileLocator points at the downloaded files of the tracks, named by name in dir.
+func FileLocator(dir string, name func(monstercat.Track) string) Locator {
+	return func(ctx context.Context, track monstercat.Track) (string, error) {
+		return filepath.Join(dir, name(track)), nil
+	}
+}
+
+// Playlist Writer.
+//
+// Writes tracks as playlist entries as they come. M3U8 and PLS take locations
+// as they are, XSPF turns file paths into file urls.
+type PlaylistWriter struct {
+	w      io.Writer
+	format PlaylistFormat
+	locate Locator
+	title  string
+	count  int
+	closed bool
+}
+
+// NewPlaylistWriter returns a writer of playlist entries located by locate.
+func NewPlaylistWriter(w io.Writer, format PlaylistFormat, locate Locator, opts ...Option) (*PlaylistWriter, error) {
+	o := newOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	format, err := ParsePlaylistFormat(string(format))
+	if err != nil {
+		return nil, err
+	}
+	if locate == nil {
+		return nil, fmt.Errorf("locator is required")
+	}
+
+	return &PlaylistWriter{w: w, format: format, locate: locate, title: o.title}, nil
+}
+
+// Write adds the tracks to the playlist.
+func (pw *PlaylistWriter) Write(ctx context.Context, tracks ...monstercat.Track) error {
+	if pw.closed {
+		return fmt.Errorf("playlist writer is closed")
+	}
+
+	for _, track := range tracks {
+		location, err := pw.locate(ctx, track)
+		if err != nil {
+			return fmt.Errorf("error locating track %s: %w", track.ID, err)
+		}
+
+		if pw.count == 0 {
+			if err := pw.writeHeader(); err != nil {
+				return err
+			}
+		}
+		pw.count++
+
+		switch pw.format {
+		case M3U8:
+			err = pw.writeM3U8(track, location)
+		case XSPF:
+			err = pw.writeXSPF(track, location)
+		case PLS:
+			err = pw.writePLS(track, location)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close finishes the playlist. It does not close the writer.
+func (pw *PlaylistWriter) Close() error {
+	if pw.closed {
+		return nil
+	}
+	pw.closed = true
+
+	if pw.count == 0 {
+		if err := pw.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch pw.format {
+	case XSPF:
+		_, err = io.WriteString(pw.w, "  </trackList>\n</playlist>\n")
+	case PLS:
+		_, err = fmt.Fprintf(pw.w, "NumberOfEntries=%d\nVersion=2\n", pw.count)
+	}
+	return err
+}
+
+func (pw *PlaylistWriter) writeHeader() error {
+	var err error
+	switch pw.format {
+	case M3U8:
+		header := "#EXTM3U\n"
+		if len(pw.title) != 0 {
+			header += "#PLAYLIST:" + singleLine(pw.title) + "\n"
+		}
+		_, err = io.WriteString(pw.w, header)
+	case XSPF:
+		header := xml.Header + "<playlist version=\"1\" xmlns=\"http://xspf.org/ns/0/\">\n"
+		if len(pw.title) != 0 {
+			header += "  <title>" + escapeXML(pw.title) + "</title>\n"
+		}
+		_, err = io.WriteString(pw.w, header+"  <trackList>\n")
+	case PLS:
+		_, err = io.WriteString(pw.w, "[playlist]\n")
+	}
+	return err
+}
+
+func (pw *PlaylistWriter) writeM3U8(track monstercat.Track, location string) error {
+	// -1 is the length of entries of unknown duration.
+	duration := track.Duration
+	if duration <= 0 {
+		duration = -1
+	}
+	_, err := fmt.Fprintf(pw.w, "#EXTINF:%d,%s\n%s\n", duration, singleLine(entryTitle(track)), location)
+	return err
+}
+
+type xspfTrack struct {
+	XMLName    xml.Name `xml:"track"`
+	Location   string   `xml:"location"`
+	Identifier string   `xml:"identifier,omitempty"`
+	Title      string   `xml:"title,omitempty"`
+	Creator    string   `xml:"creator,omitempty"`
+	Album      string   `xml:"album,omitempty"`
+	TrackNum   int      `xml:"trackNum,omitempty"`
+	Duration   int      `xml:"duration,omitempty"`
+}
+
+func (pw *PlaylistWriter) writeXSPF(track monstercat.Track, location string) error {
+	b, err := xml.MarshalIndent(xspfTrack{
+		Location:   xspfLocation(location),
+		Identifier: track.ISRC,
+		Title:      track.Title,
+		Creator:    track.ArtistsTitle,
+		Album:      track.Release.Title,
+		TrackNum:   track.TrackNumber,
+		Duration:   track.Duration * 1000, // milliseconds
+	}, "    ", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(pw.w, "%s\n", b)
+	return err
+}
+
+func (pw *PlaylistWriter) writePLS(track monstercat.Track, location string) error {
+	duration := track.Duration
+	if duration <= 0 {
+		duration = -1
+	}
+	n := pw.count
+	_, err := fmt.Fprintf(pw.w, "File%d=%s\nTitle%d=%s\nLength%d=%d\n", n, location, n, singleLine(entryTitle(track)), n, duration)
+	return err
+}
+
+// WritePlaylist writes the tracks as a playlist to w.
+func WritePlaylist(ctx context.Context, w io.Writer, format PlaylistFormat, tracks []monstercat.Track, locate Locator, opts ...Option) error {
+	pw, err := NewPlaylistWriter(w, format, locate, opts...)
+	if err != nil {
+		return err
+	}
+	if err := pw.Write(ctx, tracks...); err != nil {
+		return err
+	}
+	return pw.Close()
+}
+
+// WriteReleasePlaylist writes the tracks of the release as a playlist titled after it.
+func WriteReleasePlaylist(ctx context.Context, w io.Writer, format PlaylistFormat, release monstercat.ReleaseInfo, locate Locator, opts ...Option) error {
+	opts = append([]Option{WithTitle(release.Title)}, opts...)
+	return WritePlaylist(ctx, w, format, release.Tracks, locate, opts...)
+}
+
+// WriteSearchPlaylist writes the search results and every page after them as a playlist.
+func WriteSearchPlaylist(ctx context.Context, w io.Writer, format PlaylistFormat, results monstercat.SearchCatalogResults, locate Locator, opts ...Option) error {
+	pw, err := NewPlaylistWriter(w, format, locate, opts...)
+	if err != nil {
+		return err
+	}
+
+	for {
+		if err := pw.Write(ctx, results.Tracks...); err != nil {
+			return err
+		}
+		if !results.HasNext {
+			break
+		}
+		if results, err = results.Next(ctx); err != nil {
+			return err
+		}
+	}
+
+	return pw.Close()
+}
+
+func entryTitle(track monstercat.Track) string {
+	if len(track.ArtistsTitle) == 0 {
+		return track.Title
+	}
+	return track.ArtistsTitle + " - " + track.Title
+}
+
+// xspfLocation returns the location as a uri, turning file paths into file urls.
+func xspfLocation(location string) string {
+	if u, err := url.Parse(location); err == nil && len(u.Scheme) > 1 {
+		return location
+	}
+
+	p := filepath.ToSlash(location)
+	if filepath.IsAbs(location) {
+		if !strings.HasPrefix(p, "/") {
+			// windows drive paths.
+			p = "/" + p
+		}
+		return (&url.URL{Scheme: "file", Path: path.Clean(p)}).String()
+	}
+	return (&url.URL{Path: p}).String()
+}
+
+// singleLine keeps line based formats intact when titles hold line breaks.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func escapeXML(s string) string {
+	b := new(strings.Builder)
+	xml.EscapeText(b, []byte(s))
+	return b.String()
+}
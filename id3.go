@@ -0,0 +1,208 @@
+package monstercat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ID3 Tag.
+//
+// Holds the metadata written into the ID3v2.4 tag of a downloaded MP3.
+type ID3Tag struct {
+	Title       string
+	Artists     []string
+	Album       string
+	TrackNumber int
+	BPM         int
+	Genres      []string
+	ISRC        string
+	ReleaseDate time.Time
+	Label       string
+	Cover       []byte
+	CoverMIME   string
+}
+
+// NewID3Tag returns an ID3 tag populated from the provided track.
+func NewID3Tag(track Track) ID3Tag {
+	artists := make([]string, 0)
+	for _, artist := range track.Artists {
+		if len(artist.Name) != 0 {
+			artists = append(artists, artist.Name)
+		}
+	}
+	if len(artists) == 0 && len(track.ArtistsTitle) != 0 {
+		artists = append(artists, track.ArtistsTitle)
+	}
+
+	genres := make([]string, 0)
+	for _, genre := range []string{track.GenrePrimary, track.GenreSecondary} {
+		if len(genre) != 0 {
+			genres = append(genres, genre)
+		}
+	}
+
+	return ID3Tag{
+		Title:       track.Title,
+		Artists:     artists,
+		Album:       track.Release.Title,
+		TrackNumber: track.TrackNumber,
+		BPM:         track.BPM,
+		Genres:      genres,
+		ISRC:        track.ISRC,
+		ReleaseDate: track.Release.ReleaseDate,
+		Label:       track.Brand,
+	}
+}
+
+// WriteTaggedMP3 copies the MP3 from src to dst, replacing any existing ID3v2 tag with the provided one.
+func WriteTaggedMP3(dst io.Writer, src io.Reader, tag ID3Tag) (int64, error) {
+	header, err := tag.encode()
+	if err != nil {
+		return 0, err
+	}
+
+	audio, err := skipID3v2(src)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := dst.Write(header)
+	if err != nil {
+		return int64(n), err
+	}
+
+	m, err := io.Copy(dst, audio)
+	return int64(n) + m, err
+}
+
+type id3TextFrame struct {
+	id     string
+	values []string
+}
+
+func (t ID3Tag) encode() ([]byte, error) {
+	frames := new(bytes.Buffer)
+
+	textFrames := []id3TextFrame{
+		{"TIT2", []string{t.Title}},
+		{"TPE1", t.Artists},
+		{"TALB", []string{t.Album}},
+		{"TCON", t.Genres},
+		{"TSRC", []string{t.ISRC}},
+		{"TPUB", []string{t.Label}},
+	}
+	if t.TrackNumber > 0 {
+		textFrames = append(textFrames, id3TextFrame{"TRCK", []string{strconv.Itoa(t.TrackNumber)}})
+	}
+	if t.BPM > 0 {
+		textFrames = append(textFrames, id3TextFrame{"TBPM", []string{strconv.Itoa(t.BPM)}})
+	}
+	if !t.ReleaseDate.IsZero() {
+		date := t.ReleaseDate.UTC().Format("2006-01-02")
+		textFrames = append(textFrames, id3TextFrame{"TDRC", []string{date}}, id3TextFrame{"TDRL", []string{date}})
+	}
+
+	for _, f := range textFrames {
+		values := make([]string, 0, len(f.values))
+		for _, v := range f.values {
+			if v = strings.TrimSpace(v); len(v) != 0 {
+				values = append(values, v)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		// 0x03 is UTF-8, multiple values are separated by a null byte in v2.4.
+		body := append([]byte{0x03}, []byte(strings.Join(values, "\x00"))...)
+		if err := writeID3Frame(frames, f.id, body); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(t.Cover) != 0 {
+		mime := t.CoverMIME
+		if len(mime) == 0 {
+			mime = "image/jpeg"
+		}
+
+		body := new(bytes.Buffer)
+		body.WriteByte(0x03) // UTF-8 description
+		body.WriteString(mime)
+		body.WriteByte(0x00)
+		body.WriteByte(0x03) // front cover
+		body.WriteByte(0x00) // empty description
+		body.Write(t.Cover)
+		if err := writeID3Frame(frames, "APIC", body.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	size, err := synchsafe(frames.Len())
+	if err != nil {
+		return nil, fmt.Errorf("id3 tag is too large")
+	}
+
+	out := make([]byte, 0, 10+frames.Len())
+	out = append(out, 'I', 'D', '3', 0x04, 0x00, 0x00)
+	out = append(out, size...)
+	out = append(out, frames.Bytes()...)
+	return out, nil
+}
+
+func writeID3Frame(w *bytes.Buffer, id string, body []byte) error {
+	size, err := synchsafe(len(body))
+	if err != nil {
+		return fmt.Errorf("id3 frame %s is too large", id)
+	}
+
+	w.WriteString(id)
+	w.Write(size)
+	w.Write([]byte{0x00, 0x00}) // flags
+	w.Write(body)
+	return nil
+}
+
+// synchsafe encodes n as a 4 byte synchsafe integer (7 bits per byte).
+func synchsafe(n int) ([]byte, error) {
+	if n < 0 || n > 0x0FFFFFFF {
+		return nil, fmt.Errorf("size out of range")
+	}
+
+	return []byte{
+		byte(n >> 21 & 0x7F),
+		byte(n >> 14 & 0x7F),
+		byte(n >> 7 & 0x7F),
+		byte(n & 0x7F),
+	}, nil
+}
+
+// skipID3v2 returns a reader positioned after any leading ID3v2 tag in r.
+func skipID3v2(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(10)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	if len(header) < 10 || string(header[:3]) != "ID3" {
+		return br, nil
+	}
+
+	size := int64(header[6])<<21 | int64(header[7])<<14 | int64(header[8])<<7 | int64(header[9])
+	size += 10
+	if header[5]&0x10 != 0 {
+		size += 10 // footer
+	}
+
+	if _, err := io.CopyN(io.Discard, br, size); err != nil {
+		return nil, fmt.Errorf("error skipping existing id3 tag: %w", err)
+	}
+
+	return br, nil
+}
@@ -0,0 +1,153 @@
+package monstercat
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Image.
+type Image struct {
+	Data     []byte
+	MIMEType string
+	Width    int
+	Height   int
+	ETag     string
+}
+
+// GetCoverImage returns the release cover resized with the provided options.
+func (c *Client) GetCoverImage(ctx context.Context, release Release, options ...ResizeOption) (Image, error) {
+	buf := new(bytes.Buffer)
+	img, err := c.WriteCoverImage(ctx, release, buf, options...)
+	if err != nil {
+		return Image{}, err
+	}
+
+	img.Data = buf.Bytes()
+	return img, nil
+}
+
+// WriteCoverImage writes the release cover resized with the provided options to w.
+// The returned image has no Data, as the bytes went to w.
+func (c *Client) WriteCoverImage(ctx context.Context, release Release, w io.Writer, options ...ResizeOption) (Image, error) {
+	if len(release.CoverURL) == 0 {
+		return Image{}, fmt.Errorf("cover url is empty for release")
+	}
+
+	return c.writeImage(ctx, release.CoverURL, w, options...)
+}
+
+func (c *Client) writeImage(ctx context.Context, imageURL string, w io.Writer, options ...ResizeOption) (Image, error) {
+	u, err := c.GetResizedImageURL(ctx, imageURL, options...)
+	if err != nil {
+		return Image{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return Image{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Image{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Image{}, fmt.Errorf("invalid image")
+	}
+
+	// keep the start of the image around to read its dimensions.
+	head := &headWriter{max: 64 << 10}
+	if _, err := io.Copy(io.MultiWriter(w, head), resp.Body); err != nil {
+		return Image{}, err
+	}
+
+	mimeType := strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	if len(mimeType) == 0 || mimeType == "application/octet-stream" {
+		mimeType = http.DetectContentType(head.buf.Bytes())
+	}
+
+	width, height := imageDimensions(head.buf.Bytes())
+	return Image{
+		MIMEType: mimeType,
+		Width:    width,
+		Height:   height,
+		ETag:     resp.Header.Get("ETag"),
+	}, nil
+}
+
+// headWriter keeps the first max bytes written to it.
+type headWriter struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (h *headWriter) Write(p []byte) (int, error) {
+	if remaining := h.max - h.buf.Len(); remaining > 0 {
+		if len(p) > remaining {
+			h.buf.Write(p[:remaining])
+		} else {
+			h.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// imageDimensions reads the dimensions from the image header, or returns zeros when unknown.
+func imageDimensions(data []byte) (int, int) {
+	if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return cfg.Width, cfg.Height
+	}
+
+	if len(data) >= 30 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP" {
+		return webpDimensions(data)
+	}
+
+	if len(data) >= 12 && string(data[4:12]) == "ftypavif" {
+		return avifDimensions(data)
+	}
+
+	return 0, 0
+}
+
+func webpDimensions(data []byte) (int, int) {
+	switch string(data[12:16]) {
+	case "VP8 ":
+		// lossy, 14 bit sizes after the 3 byte frame tag and start code.
+		w := int(binary.LittleEndian.Uint16(data[26:28]) & 0x3FFF)
+		h := int(binary.LittleEndian.Uint16(data[28:30]) & 0x3FFF)
+		return w, h
+	case "VP8L":
+		// lossless, 14 bit sizes minus one packed after the signature byte.
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		return int(bits&0x3FFF) + 1, int(bits>>14&0x3FFF) + 1
+	case "VP8X":
+		// extended, 24 bit canvas sizes minus one.
+		w := int(data[24]) | int(data[25])<<8 | int(data[26])<<16
+		h := int(data[27]) | int(data[28])<<8 | int(data[29])<<16
+		return w + 1, h + 1
+	}
+
+	return 0, 0
+}
+
+func avifDimensions(data []byte) (int, int) {
+	// the image spatial extents property holds version and flags, then 32 bit sizes.
+	i := bytes.Index(data, []byte("ispe"))
+	if i < 0 || len(data) < i+16 {
+		return 0, 0
+	}
+
+	w := binary.BigEndian.Uint32(data[i+8 : i+12])
+	h := binary.BigEndian.Uint32(data[i+12 : i+16])
+	return int(w), int(h)
+}
@@ -0,0 +1,69 @@
+package monstercat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// List Followed Artists API Response.
+type listFollowedArtistsAPIResponse struct {
+	Data []artistAPIResponse `json:"Data"`
+}
+
+// ListLikedTracks returns the first page of liked tracks of the signed in account, WithLimit and WithOffset control the page.
+func (c *Client) ListLikedTracks(ctx context.Context, opts ...Option) (TrackPage, error) {
+	options := newOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	return c.getTrackPage(ctx, "self/liked-tracks", options)
+}
+
+// LikeTrack adds the track to the liked tracks of the signed in account.
+func (c *Client) LikeTrack(ctx context.Context, track Track) error {
+	return c.setTrackLiked(ctx, track, http.MethodPost)
+}
+
+// UnlikeTrack removes the track from the liked tracks of the signed in account.
+func (c *Client) UnlikeTrack(ctx context.Context, track Track) error {
+	return c.setTrackLiked(ctx, track, http.MethodDelete)
+}
+
+// ListFollowedArtists returns the artists followed by the signed in account.
+func (c *Client) ListFollowedArtists(ctx context.Context) ([]Artist, error) {
+	req, err := c.makeRequest(ctx, "self/following", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	apiResponse := new(listFollowedArtistsAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return nil, err
+	}
+
+	artists := make([]Artist, 0)
+	for _, result := range apiResponse.Data {
+		artists = append(artists, result.toArtist())
+	}
+
+	return artists, nil
+}
+
+func (c *Client) setTrackLiked(ctx context.Context, track Track, method string) error {
+	if len(track.ID) == 0 {
+		return fmt.Errorf("track id is empty for track")
+	}
+
+	if len(track.Release.ID) == 0 {
+		return fmt.Errorf("release id is empty for track")
+	}
+
+	body := playlistItem{ReleaseID: track.Release.ID, TrackID: track.ID}
+	req, err := c.makeJSONRequest(ctx, method, "self/liked-tracks", body)
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(req, nil)
+}
@@ -0,0 +1,154 @@
+package monstercat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type LicensePlatform string
+
+// license platforms.
+const (
+	LicenseYouTube LicensePlatform = "YouTube"
+	LicenseTwitch  LicensePlatform = "Twitch"
+)
+
+// License.
+//
+// A creator license whitelisting a channel for the catalog.
+type License struct {
+	ID         string
+	Platform   LicensePlatform
+	ChannelURL string
+	Name       string
+	Active     bool
+	CreatedAt  time.Time
+}
+
+// License API Response.
+type licenseAPIResponse struct {
+	ID        string    `json:"Id"`
+	Vendor    string    `json:"Vendor"`
+	Identity  string    `json:"Identity"`
+	Name      string    `json:"Name"`
+	State     string    `json:"State"`
+	CreatedAt time.Time `json:"CreatedAt"`
+}
+
+// List Licenses API Response.
+type listLicensesAPIResponse struct {
+	Data []licenseAPIResponse `json:"Data"`
+}
+
+func (r *licenseAPIResponse) toLicense() License {
+	return License{
+		ID:         r.ID,
+		Platform:   LicensePlatform(r.Vendor),
+		ChannelURL: r.Identity,
+		Name:       r.Name,
+		Active:     strings.EqualFold(r.State, "active"),
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+// ListLicenses returns the creator licenses of the signed in account.
+func (c *Client) ListLicenses(ctx context.Context) ([]License, error) {
+	req, err := c.makeRequest(ctx, "self/licenses", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	apiResponse := new(listLicensesAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return nil, err
+	}
+
+	licenses := make([]License, 0)
+	for _, result := range apiResponse.Data {
+		licenses = append(licenses, result.toLicense())
+	}
+
+	return licenses, nil
+}
+
+// AddLicense whitelists a YouTube or Twitch channel on the signed in account.
+func (c *Client) AddLicense(ctx context.Context, channelURL string) (License, error) {
+	platform, err := licensePlatformFromURL(channelURL)
+	if err != nil {
+		return License{}, err
+	}
+
+	body := map[string]string{
+		"Vendor":   string(platform),
+		"Identity": strings.TrimSpace(channelURL),
+	}
+	req, err := c.makeJSONRequest(ctx, http.MethodPost, "self/licenses", body)
+	if err != nil {
+		return License{}, err
+	}
+
+	apiResponse := new(licenseAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return License{}, err
+	}
+
+	return apiResponse.toLicense(), nil
+}
+
+// RemoveLicense removes the license from the signed in account.
+func (c *Client) RemoveLicense(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if len(id) == 0 {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	req, err := c.makeJSONRequest(ctx, http.MethodDelete, fmt.Sprintf("self/licenses/%s", id), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(req, nil)
+}
+
+// CheckTrackCleared reports whether the track can be used on the platform,
+// which needs a creator friendly track and an active license for the platform.
+func (c *Client) CheckTrackCleared(ctx context.Context, track Track, platform LicensePlatform) (bool, error) {
+	if !track.CreatorFriendly {
+		return false, nil
+	}
+
+	licenses, err := c.ListLicenses(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, license := range licenses {
+		if license.Active && license.Platform == platform {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func licensePlatformFromURL(channelURL string) (LicensePlatform, error) {
+	u, err := url.Parse(strings.TrimSpace(channelURL))
+	if err != nil || len(u.Host) == 0 || len(strings.Trim(u.Path, "/")) == 0 {
+		return "", fmt.Errorf("invalid channel url")
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	switch host {
+	case "youtube.com":
+		return LicenseYouTube, nil
+	case "twitch.tv":
+		return LicenseTwitch, nil
+	default:
+		return "", fmt.Errorf("channel url must be a YouTube or Twitch channel")
+	}
+}
@@ -1,11 +1,13 @@
 package monstercat
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"strconv"
 	"strings"
 )
@@ -19,14 +21,30 @@ const (
 // Monstercat Client.
 type Client struct {
 	httpClient *http.Client
+	baseURL    string
 }
 
 // NewClient returns a new monstercat client.
-func NewClient(c *http.Client) *Client {
+func NewClient(c *http.Client, options ...ClientOption) *Client {
 	if c == nil {
 		c = &http.Client{}
 	}
-	return &Client{c}
+	if c.Jar == nil {
+		// sessions live in cookies, add a jar without touching the caller's client.
+		withJar := *c
+		withJar.Jar, _ = cookiejar.New(nil)
+		c = &withJar
+	}
+
+	opts := newClientOpts()
+	for _, option := range options {
+		option(opts)
+	}
+
+	return &Client{
+		httpClient: c,
+		baseURL:    opts.baseURL,
+	}
 }
 
 // SearchCatalog returns catalog search results for the provided query and optional search options.
@@ -38,7 +56,7 @@ func (c *Client) SearchCatalog(ctx context.Context, q string, opts ...Option) (S
 	return c.searchCatalog(ctx, q, options)
 }
 
-// GetTrackStream
+// GetTrackStream returns the audio stream for the track. The caller must close it, cancelling ctx also closes it.
 func (c *Client) GetTrackStream(ctx context.Context, track Track) (io.ReadCloser, error) {
 	if len(track.ID) == 0 {
 		return nil, fmt.Errorf("track id is empty for track")
@@ -48,80 +66,62 @@ func (c *Client) GetTrackStream(ctx context.Context, track Track) (io.ReadCloser
 		return nil, fmt.Errorf("release id is empty for track")
 	}
 
-	req, err := makeRequest(ctx, fmt.Sprintf("release/%s/track-stream/%s", track.Release.ID, track.ID), nil)
+	req, err := c.makeRequest(ctx, fmt.Sprintf("release/%s/track-stream/%s", track.Release.ID, track.ID), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	r, w := io.Pipe()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		w.CloseWithError(err)
 		return nil, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
-		errInvalidTrack := fmt.Errorf("invalid track")
-		w.CloseWithError(errInvalidTrack)
-		return nil, errInvalidTrack
+		return nil, fmt.Errorf("invalid track")
 	}
 
-	go func() {
-		defer resp.Body.Close()
-		_, copyErr := io.Copy(w, resp.Body)
-		if copyErr != nil {
-			w.CloseWithError(copyErr)
-			return
-		}
-
-		w.Close()
-	}()
-
-	return r, nil
+	return newStreamBody(ctx, resp.Body), nil
 }
 
-// GetTrackStreamURL
-func (c *Client) GetTrackStreamURL(ctx context.Context, track Track) (string, error) {
+// GetTrackStreamURL returns the signed stream url for the track along with its expiry.
+func (c *Client) GetTrackStreamURL(ctx context.Context, track Track) (StreamURL, error) {
 	if len(track.ID) == 0 {
-		return "", fmt.Errorf("track id is empty for track")
+		return StreamURL{}, fmt.Errorf("track id is empty for track")
 	}
 
 	if len(track.Release.ID) == 0 {
-		return "", fmt.Errorf("release id is empty for track")
+		return StreamURL{}, fmt.Errorf("release id is empty for track")
 	}
 
 	params := make(map[string]string)
 	params["noRedirect"] = "true"
 
-	req, err := makeRequest(ctx, fmt.Sprintf("release/%s/track-stream/%s", track.Release.ID, track.ID), params)
+	req, err := c.makeRequest(ctx, fmt.Sprintf("release/%s/track-stream/%s", track.Release.ID, track.ID), params)
 	if err != nil {
-		return "", err
+		return StreamURL{}, err
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", err
+		return StreamURL{}, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("invalid track")
+		return StreamURL{}, fmt.Errorf("invalid track")
 	}
 
-	type trackStreamURL struct {
-		SignedURL string `json:"SignedURL"`
-	}
-	res := new(trackStreamURL)
-	err = json.NewDecoder(resp.Body).Decode(res)
+	apiResponse := new(streamURLAPIResponse)
+	err = json.NewDecoder(resp.Body).Decode(apiResponse)
 	if err != nil {
-		return "", err
+		return StreamURL{}, err
 	}
 
-	return res.SignedURL, nil
+	return apiResponse.toStreamURL(c, track)
 }
 
-// GetResizedImage
+// GetResizedImageURL returns the CDX url of the image resized with the provided options.
 func (c *Client) GetResizedImageURL(ctx context.Context, coverURL string, options ...ResizeOption) (string, error) {
 	opts := newResizeOptions()
 	for _, option := range options {
@@ -143,17 +143,18 @@ func (c *Client) GetResizedImageURL(ctx context.Context, coverURL string, option
 	params.Set("encoding", string(opts.encoding))
 	req.URL.RawQuery = params.Encode()
 
-	client := &http.Client{}
+	// reuse the configured client, but stop at the redirect to read its location.
+	client := *c.httpClient
 	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", nil
+		return "", err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusPermanentRedirect {
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
 		return "", fmt.Errorf("failed to get resized image url")
 	}
 
@@ -182,7 +183,7 @@ func (c *Client) searchCatalog(ctx context.Context, q string, opts *options) (Se
 		return SearchCatalogResults{}, err
 	}
 
-	req, err := makeRequest(ctx, "catalog/browse", params)
+	req, err := c.makeRequest(ctx, "catalog/browse", params)
 	if err != nil {
 		return SearchCatalogResults{}, err
 	}
@@ -208,7 +209,7 @@ func (c *Client) getRelease(ctx context.Context, id string, opts *getReleaseOpts
 		return ReleaseInfo{}, fmt.Errorf("id cannot be empty")
 	}
 
-	req, err := makeRequest(ctx, fmt.Sprintf("catalog/release/%s", id), opts.build())
+	req, err := c.makeRequest(ctx, fmt.Sprintf("catalog/release/%s", id), opts.build())
 	if err != nil {
 		return ReleaseInfo{}, err
 	}
@@ -232,8 +233,8 @@ func (c *Client) getRelease(ctx context.Context, id string, opts *getReleaseOpts
 	return apiResponse.toReleaseInfo(ctx, c)
 }
 
-func makeRequest(ctx context.Context, url string, params map[string]string) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s", baseURL, url), nil)
+func (c *Client) makeRequest(ctx context.Context, url string, params map[string]string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s", c.baseURL, url), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -247,6 +248,55 @@ func makeRequest(ctx context.Context, url string, params map[string]string) (*ht
 	return req, nil
 }
 
+func (c *Client) makeJSONRequest(ctx context.Context, method string, url string, body any) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", c.baseURL, url), r)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// doJSON sends the request and decodes a successful response into out, when provided.
+func (c *Client) doJSON(req *http.Request, out any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return ErrUnauthorized
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiError := new(errorAPIResponse)
+		if err := json.NewDecoder(resp.Body).Decode(apiError); err == nil && len(apiError.Message) != 0 {
+			return fmt.Errorf("request failed: %s", apiError.Message)
+		}
+		return fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
 func buildParams(opts *options) (map[string]string, error) {
 	err := opts.validate()
 	if err != nil {
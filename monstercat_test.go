@@ -1,9 +1,26 @@
 package monstercat_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ppalone/monstercat"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +31,20 @@ func Test_NewClient(t *testing.T) {
 	assert.NotNil(t, c)
 }
 
+func Test_NewClientWithBaseURL(t *testing.T) {
+	var requested string
+	c := monstercat.NewClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			requested = req.URL.String()
+			return newFakeResponse(req, http.StatusOK, `{"Data": []}`), nil
+		}),
+	}, monstercat.WithBaseURL("https://staging.example.com/api/"))
+
+	_, err := c.SearchCatalog(context.Background(), "")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(requested, "https://staging.example.com/api/catalog/browse?"))
+}
+
 func Test_SearchCatalog(t *testing.T) {
 	t.Run("with no options", func(t *testing.T) {
 		q := "Nitro Fun"
@@ -149,7 +180,7 @@ func Test_GetTrackStreamURL(t *testing.T) {
 		track := res.Tracks[0]
 		u, err := c.GetTrackStreamURL(context.Background(), track)
 		assert.NoError(t, err)
-		assert.NotEmpty(t, u)
+		assert.NotEmpty(t, u.URL)
 	})
 
 	t.Run("with invalid id", func(t *testing.T) {
@@ -191,6 +222,41 @@ func Test_GetResizedImageURL(t *testing.T) {
 	}
 }
 
+func Test_GetResizedImageURLWithClient(t *testing.T) {
+	newClient := func(status int, err error) *monstercat.Client {
+		return monstercat.NewClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if err != nil {
+					return nil, err
+				}
+				resp := newFakeResponse(req, status, "")
+				resp.Header.Set("Location", "https://cdx.example.com/resized.webp")
+				return resp, nil
+			}),
+		})
+	}
+
+	t.Run("with any redirect", func(t *testing.T) {
+		for _, status := range []int{http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect} {
+			u, err := newClient(status, nil).GetResizedImageURL(context.Background(), "https://www.monstercat.com/release/x/cover")
+			assert.NoError(t, err)
+			assert.Equal(t, "https://cdx.example.com/resized.webp", u)
+		}
+	})
+
+	t.Run("with non redirect", func(t *testing.T) {
+		u, err := newClient(http.StatusOK, nil).GetResizedImageURL(context.Background(), "https://www.monstercat.com/release/x/cover")
+		assert.ErrorContains(t, err, "failed to get resized image url")
+		assert.Empty(t, u)
+	})
+
+	t.Run("with transport error", func(t *testing.T) {
+		u, err := newClient(0, io.ErrUnexpectedEOF).GetResizedImageURL(context.Background(), "https://www.monstercat.com/release/x/cover")
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Empty(t, u)
+	})
+}
+
 func Test_GetRelease(t *testing.T) {
 	t.Run("with catalog id", func(t *testing.T) {
 		c := monstercat.NewClient(nil)
@@ -230,3 +296,1147 @@ func Test_GetRelease(t *testing.T) {
 		assert.ElementsMatch(t, res1.Tracks, res2.Tracks)
 	})
 }
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newFakeResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func Test_DownloadTrack(t *testing.T) {
+	// existing ID3v2.3 tag with a 4 byte body followed by the audio frames
+	served := "ID3\x03\x00\x00\x00\x00\x00\x04oldt" + "audio-frames"
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return newFakeResponse(req, http.StatusOK, served), nil
+		}),
+	}
+
+	track := monstercat.Track{
+		ID:          "track",
+		Title:       "Emoji",
+		TrackNumber: 1,
+		BPM:         128,
+		Artists:     []monstercat.Artist{{Name: "Pegboard Nerds"}},
+		Release:     monstercat.Release{ID: "release", Title: "Emoji"},
+	}
+
+	t.Run("without tags", func(t *testing.T) {
+		c := monstercat.NewClient(httpClient)
+		buf := new(bytes.Buffer)
+		n, err := c.DownloadTrack(context.Background(), track, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(served)), n)
+		assert.Equal(t, served, buf.String())
+	})
+
+	t.Run("with tags", func(t *testing.T) {
+		c := monstercat.NewClient(httpClient)
+		buf := new(bytes.Buffer)
+		n, err := c.DownloadTrack(context.Background(), track, buf, monstercat.WithTags())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(buf.Len()), n)
+
+		out := buf.Bytes()
+		assert.Equal(t, "ID3\x04", string(out[:4]))
+		assert.Contains(t, buf.String(), "TIT2")
+		assert.Contains(t, buf.String(), "Pegboard Nerds")
+		assert.Contains(t, buf.String(), "TBPM")
+		assert.NotContains(t, buf.String(), "oldt")
+		assert.True(t, bytes.HasSuffix(out, []byte("audio-frames")))
+	})
+}
+
+// blockingBody blocks on Read until it is closed.
+type blockingBody struct {
+	closed chan struct{}
+}
+
+func newBlockingBody() *blockingBody {
+	return &blockingBody{closed: make(chan struct{})}
+}
+
+func (b *blockingBody) Read(p []byte) (int, error) {
+	<-b.closed
+	return 0, io.ErrClosedPipe
+}
+
+func (b *blockingBody) Close() error {
+	select {
+	case <-b.closed:
+	default:
+		close(b.closed)
+	}
+	return nil
+}
+
+func Test_GetTrackStreamTeardown(t *testing.T) {
+	track := monstercat.Track{ID: "track", Release: monstercat.Release{ID: "release"}}
+
+	newClient := func(body *blockingBody) *monstercat.Client {
+		return monstercat.NewClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				resp := newFakeResponse(req, http.StatusOK, "")
+				resp.Body = body
+				return resp, nil
+			}),
+		})
+	}
+
+	assertClosed := func(t *testing.T, body *blockingBody) {
+		select {
+		case <-body.closed:
+		case <-time.After(time.Second):
+			t.Fatal("response body was not closed")
+		}
+	}
+
+	t.Run("abandoned reader does not leak", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		for i := 0; i < 50; i++ {
+			ctx, cancel := context.WithCancel(context.Background())
+			body := newBlockingBody()
+			_, err := newClient(body).GetTrackStream(ctx, track)
+			assert.NoError(t, err)
+			cancel()
+			assertClosed(t, body)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+	})
+
+	t.Run("close tears down body", func(t *testing.T) {
+		body := newBlockingBody()
+		stream, err := newClient(body).GetTrackStream(context.Background(), track)
+		assert.NoError(t, err)
+		assert.NoError(t, stream.Close())
+		assertClosed(t, body)
+	})
+
+	t.Run("cancel unblocks pending read", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		body := newBlockingBody()
+		stream, err := newClient(body).GetTrackStream(ctx, track)
+		assert.NoError(t, err)
+
+		done := make(chan error)
+		go func() {
+			_, err := stream.Read(make([]byte, 16))
+			done <- err
+		}()
+
+		cancel()
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			t.Fatal("read was not unblocked by cancel")
+		}
+		assertClosed(t, body)
+	})
+}
+
+func Test_StreamURLExpiry(t *testing.T) {
+	signed := time.Now().UTC().Truncate(time.Second)
+	calls := 0
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			calls++
+			body := fmt.Sprintf(`{"SignedURL": "https://cdn.example.com/track.mp3?X-Amz-Date=%s&X-Amz-Expires=%d"}`, signed.Format("20060102T150405Z"), 60*(calls))
+			return newFakeResponse(req, http.StatusOK, body), nil
+		}),
+	}
+	track := monstercat.Track{ID: "track", Release: monstercat.Release{ID: "release"}}
+
+	t.Run("parses expiry and content type", func(t *testing.T) {
+		calls = 0
+		c := monstercat.NewClient(httpClient)
+		u, err := c.GetTrackStreamURL(context.Background(), track)
+		assert.NoError(t, err)
+		assert.Equal(t, signed.Add(time.Minute), u.Expires.UTC())
+		assert.Equal(t, "audio/mpeg", u.ContentType)
+		assert.True(t, u.ExpiresWithin(2*time.Minute))
+
+		assert.NoError(t, u.Refresh(context.Background()))
+		assert.Equal(t, signed.Add(2*time.Minute), u.Expires.UTC())
+	})
+
+	t.Run("cache re-signs before expiry", func(t *testing.T) {
+		calls = 0
+		c := monstercat.NewClient(httpClient)
+		cache := monstercat.NewStreamURLCache(c, 90*time.Second)
+
+		// first url expires within the margin so the second get re-signs
+		_, err := cache.Get(context.Background(), track)
+		assert.NoError(t, err)
+		_, err = cache.Get(context.Background(), track)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+
+		u, err := cache.Get(context.Background(), track)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, signed.Add(2*time.Minute), u.Expires.UTC())
+	})
+}
+
+func Test_StreamProxy(t *testing.T) {
+	audio := strings.Repeat("0123456789", 100)
+	cdnHits := 0
+	httpClient := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "cdn.example.com" {
+				cdnHits++
+				return newFakeResponse(req, http.StatusOK, audio), nil
+			}
+			if strings.Contains(req.URL.Path, "/release/missing/") {
+				return newFakeResponse(req, http.StatusNotFound, ""), nil
+			}
+			if strings.Contains(req.URL.Path, "/release/lossless/") {
+				return newFakeResponse(req, http.StatusOK, `{"SignedURL": "https://cdn.example.com/track.flac"}`), nil
+			}
+			return newFakeResponse(req, http.StatusOK, `{"SignedURL": "https://cdn.example.com/track.mp3"}`), nil
+		}),
+	}
+
+	c := monstercat.NewClient(httpClient)
+	proxy, err := monstercat.NewStreamProxy(c, t.TempDir(), 1<<20)
+	assert.NoError(t, err)
+
+	t.Run("caches first play", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracks/release/track", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, audio, rec.Body.String())
+		assert.Equal(t, "audio/mpeg", rec.Header().Get("Content-Type"))
+
+		req := httptest.NewRequest(http.MethodGet, "/tracks/release/track", nil)
+		req.Header.Set("Range", "bytes=10-19")
+		rec = httptest.NewRecorder()
+		proxy.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusPartialContent, rec.Code)
+		assert.Equal(t, "0123456789", rec.Body.String())
+		assert.Equal(t, 1, cdnHits)
+	})
+
+	t.Run("with stream content type", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			rec := httptest.NewRecorder()
+			proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracks/lossless/track", nil))
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "audio/flac", rec.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("with invalid path", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracks/release", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("with invalid id", func(t *testing.T) {
+		for _, path := range []string{"/tracks/release/..%2F..%2Fme", "/tracks/release/track%3Fdownload=1", "/tracks/..%2Frelease/track"} {
+			rec := httptest.NewRecorder()
+			proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusNotFound, rec.Code, path)
+		}
+	})
+
+	t.Run("with invalid track", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		proxy.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracks/missing/track", nil))
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+	})
+
+	t.Run("redirects tracks larger than cache", func(t *testing.T) {
+		small, err := monstercat.NewStreamProxy(c, t.TempDir(), 10)
+		assert.NoError(t, err)
+
+		rec := httptest.NewRecorder()
+		small.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tracks/release/track", nil))
+		assert.Equal(t, http.StatusTemporaryRedirect, rec.Code)
+		assert.Equal(t, "https://cdn.example.com/track.mp3", rec.Header().Get("Location"))
+	})
+}
+
+func Test_Broadcast(t *testing.T) {
+	audio := strings.Repeat("0123456789", 1000)
+
+	t.Run("with several readers", func(t *testing.T) {
+		hits := 0
+		c := monstercat.NewClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				hits++
+				return newFakeResponse(req, http.StatusOK, audio), nil
+			}),
+		})
+
+		track := monstercat.Track{ID: "track", Release: monstercat.Release{ID: "release"}}
+		b, err := c.BroadcastTrack(context.Background(), track, 256, time.Second)
+		assert.NoError(t, err)
+		defer b.Close()
+
+		readers := make([]io.ReadCloser, 3)
+		for i := range readers {
+			readers[i], err = b.NewReader()
+			assert.NoError(t, err)
+		}
+
+		results := make(chan string, len(readers))
+		for _, r := range readers {
+			go func(r io.ReadCloser) {
+				defer r.Close()
+				buf, _ := io.ReadAll(r)
+				results <- string(buf)
+			}(r)
+		}
+
+		for range readers {
+			assert.Equal(t, audio, <-results)
+		}
+		assert.Equal(t, 1, hits)
+	})
+
+	t.Run("evicts slow consumer", func(t *testing.T) {
+		b, err := monstercat.NewBroadcast(io.NopCloser(strings.NewReader(audio)), 100, 50*time.Millisecond)
+		assert.NoError(t, err)
+		defer b.Close()
+
+		fast, err := b.NewReader()
+		assert.NoError(t, err)
+		slow, err := b.NewReader()
+		assert.NoError(t, err)
+
+		buf, err := io.ReadAll(fast)
+		assert.NoError(t, err)
+		assert.Equal(t, audio, string(buf))
+
+		_, err = io.ReadAll(slow)
+		assert.ErrorIs(t, err, monstercat.ErrSlowConsumer)
+	})
+
+	t.Run("with invalid evict after", func(t *testing.T) {
+		_, err := monstercat.NewBroadcast(io.NopCloser(strings.NewReader(audio)), 100, 0)
+		assert.Error(t, err)
+	})
+
+	t.Run("close unblocks readers", func(t *testing.T) {
+		r, w := io.Pipe()
+		b, err := monstercat.NewBroadcast(r, 100, time.Second)
+		assert.NoError(t, err)
+
+		reader, err := b.NewReader()
+		assert.NoError(t, err)
+
+		done := make(chan error)
+		go func() {
+			_, err := reader.Read(make([]byte, 10))
+			done <- err
+		}()
+
+		assert.NoError(t, b.Close())
+		assert.Error(t, <-done)
+		w.Close()
+	})
+}
+
+// newImageTransport serves img as a jpeg through a fake CDX redirect.
+func newImageTransport(t *testing.T, img image.Image) roundTripFunc {
+	buf := new(bytes.Buffer)
+	err := jpeg.Encode(buf, img, &jpeg.Options{Quality: 100})
+	assert.NoError(t, err)
+
+	return func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == "cdx.monstercat.com" {
+			resp := newFakeResponse(req, http.StatusPermanentRedirect, "")
+			resp.Header.Set("Location", "https://images.example.com/cover.jpg?"+req.URL.RawQuery)
+			return resp, nil
+		}
+		if req.URL.Host == "images.example.com" {
+			resp := newFakeResponse(req, http.StatusOK, buf.String())
+			resp.Header.Set("Content-Type", "image/jpeg")
+			resp.Header.Set("ETag", `"cover"`)
+			return resp, nil
+		}
+		return newFakeResponse(req, http.StatusOK, "audio-frames"), nil
+	}
+}
+
+func Test_GetCoverImage(t *testing.T) {
+	c := monstercat.NewClient(&http.Client{Transport: newImageTransport(t, image.NewRGBA(image.Rect(0, 0, 40, 20)))})
+	release := monstercat.Release{ID: "release", CatalogID: "742779555328", CoverURL: "https://www.monstercat.com/release/742779555328/cover"}
+
+	t.Run("with bytes", func(t *testing.T) {
+		img, err := c.GetCoverImage(context.Background(), release, monstercat.WithEncoding(monstercat.JPEG))
+		assert.NoError(t, err)
+		assert.NotEmpty(t, img.Data)
+		assert.Equal(t, "image/jpeg", img.MIMEType)
+		assert.Equal(t, 40, img.Width)
+		assert.Equal(t, 20, img.Height)
+		assert.Equal(t, `"cover"`, img.ETag)
+	})
+
+	t.Run("with writer", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		img, err := c.WriteCoverImage(context.Background(), release, buf)
+		assert.NoError(t, err)
+		assert.Empty(t, img.Data)
+		assert.NotEmpty(t, buf.Bytes())
+		assert.Equal(t, 40, img.Width)
+	})
+
+	t.Run("with missing cover url", func(t *testing.T) {
+		_, err := c.GetCoverImage(context.Background(), monstercat.Release{})
+		assert.ErrorContains(t, err, "cover url is empty")
+	})
+
+	t.Run("embedded in download", func(t *testing.T) {
+		track := monstercat.Track{ID: "track", Title: "Emoji", Release: release}
+		buf := new(bytes.Buffer)
+		_, err := c.DownloadTrack(context.Background(), track, buf, monstercat.WithCoverArt(300))
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), "APIC")
+		assert.Contains(t, buf.String(), "image/jpeg")
+	})
+}
+
+func Test_CoverSrcSet(t *testing.T) {
+	c := monstercat.NewClient(&http.Client{Transport: newImageTransport(t, image.NewRGBA(image.Rect(0, 0, 40, 20)))})
+	release := monstercat.Release{CoverURL: "https://www.monstercat.com/release/742779555328/cover"}
+
+	t.Run("with snapped widths", func(t *testing.T) {
+		srcset, err := c.CoverSrcSet(context.Background(), release, 200, 256, 1000)
+		assert.NoError(t, err)
+
+		candidates := strings.Split(srcset, ", ")
+		assert.Len(t, candidates, 2)
+		assert.Contains(t, candidates[0], "width=256")
+		assert.True(t, strings.HasSuffix(candidates[0], " 256w"))
+		assert.Contains(t, candidates[1], "width=1024")
+		assert.True(t, strings.HasSuffix(candidates[1], " 1024w"))
+	})
+
+	t.Run("with out of range widths", func(t *testing.T) {
+		for _, w := range []int{-5, 0, 100000} {
+			_, err := c.GetResizedImageURL(context.Background(), release.CoverURL, monstercat.WithWidth(w))
+			assert.ErrorContains(t, err, "width must be between")
+		}
+	})
+
+	t.Run("with png and avif encodings", func(t *testing.T) {
+		for _, e := range []monstercat.ImageEncoding{monstercat.PNG, monstercat.AVIF} {
+			u, err := c.GetResizedImageURL(context.Background(), release.CoverURL, monstercat.WithEncoding(e))
+			assert.NoError(t, err)
+			assert.Contains(t, u, "encoding="+string(e))
+		}
+	})
+}
+
+func Test_ArtistImages(t *testing.T) {
+	c := monstercat.NewClient(&http.Client{Transport: newImageTransport(t, image.NewRGBA(image.Rect(0, 0, 64, 64)))})
+	artist := monstercat.Artist{Name: "Nitro Fun", URI: "nitrofun"}
+
+	t.Run("with resized profile", func(t *testing.T) {
+		u, err := c.GetArtistImageURL(context.Background(), artist, monstercat.ArtistProfile, monstercat.WithWidth(512))
+		assert.NoError(t, err)
+		assert.Contains(t, u, "width=512")
+		assert.Contains(t, u, "artist%2Fnitrofun%2Fphoto")
+	})
+
+	t.Run("with banner bytes", func(t *testing.T) {
+		img, err := c.GetArtistImage(context.Background(), artist, monstercat.ArtistBanner, monstercat.WithEncoding(monstercat.JPEG))
+		assert.NoError(t, err)
+		assert.NotEmpty(t, img.Data)
+		assert.Equal(t, 64, img.Width)
+	})
+
+	t.Run("with missing uri", func(t *testing.T) {
+		_, err := c.GetArtistImageURL(context.Background(), monstercat.Artist{}, monstercat.ArtistProfile)
+		assert.ErrorContains(t, err, "uri is empty")
+	})
+}
+
+func Test_CoverPalette(t *testing.T) {
+	// three quarters red, one quarter blue
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.RGBA{R: 200, A: 255}}, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 48, 64, 64), &image.Uniform{color.RGBA{B: 200, A: 255}}, image.Point{}, draw.Src)
+
+	hits := 0
+	transport := newImageTransport(t, img)
+	c := monstercat.NewClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			hits++
+			return transport(req)
+		}),
+	})
+	release := monstercat.Release{CatalogID: "742779555328", CoverURL: "https://www.monstercat.com/release/742779555328/cover"}
+
+	closeTo := func(t *testing.T, want, got color.RGBA) {
+		for _, d := range []int{int(want.R) - int(got.R), int(want.G) - int(got.G), int(want.B) - int(got.B)} {
+			assert.LessOrEqual(t, d*d, 16*16, "want %v, got %v", want, got)
+		}
+	}
+
+	t.Run("with dominant colors", func(t *testing.T) {
+		palette, err := c.CoverPalette(context.Background(), release, 2)
+		assert.NoError(t, err)
+		assert.Len(t, palette, 2)
+		closeTo(t, color.RGBA{R: 200, A: 255}, palette[0])
+		closeTo(t, color.RGBA{B: 200, A: 255}, palette[1])
+	})
+
+	t.Run("with cache", func(t *testing.T) {
+		hits = 0
+		cache := monstercat.NewPaletteCache(c)
+		first, err := cache.Get(context.Background(), release, 3)
+		assert.NoError(t, err)
+		second, err := cache.Get(context.Background(), release, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 2, hits) // one redirect, one image
+	})
+
+	t.Run("with invalid count", func(t *testing.T) {
+		_, err := c.CoverPalette(context.Background(), release, 0)
+		assert.Error(t, err)
+	})
+}
+
+// newAccountTransport fakes the sign in flow, "2fa@example.com" needs the code "123456".
+func newAccountTransport(t *testing.T) roundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		body := make(map[string]string)
+		if req.Body != nil {
+			json.NewDecoder(req.Body).Decode(&body)
+		}
+
+		signedIn := func(status int, payload string) *http.Response {
+			resp := newFakeResponse(req, status, payload)
+			resp.Header.Add("Set-Cookie", "cid=session; Path=/")
+			return resp
+		}
+
+		switch req.URL.Path {
+		case "/api/sign-in":
+			if body["Password"] != "secret" {
+				return newFakeResponse(req, http.StatusUnauthorized, `{"Message": "invalid credentials"}`), nil
+			}
+			if body["Email"] == "2fa@example.com" {
+				return newFakeResponse(req, http.StatusOK, `{"Needs2FA": true, "TwoFactorMethod": "totp"}`), nil
+			}
+			return signedIn(http.StatusOK, `{}`), nil
+		case "/api/sign-in/token":
+			if body["Token"] != "123456" {
+				return newFakeResponse(req, http.StatusBadRequest, `{"Message": "invalid code"}`), nil
+			}
+			return signedIn(http.StatusOK, `{}`), nil
+		case "/api/sign-out":
+			return newFakeResponse(req, http.StatusOK, `{}`), nil
+		case "/api/me":
+			if cookie, err := req.Cookie("cid"); err != nil || cookie.Value != "session" {
+				return newFakeResponse(req, http.StatusUnauthorized, `{}`), nil
+			}
+			return newFakeResponse(req, http.StatusOK, `{"User": {"Id": "user", "Email": "me@example.com", "HasGold": true}, "Subscription": {"Status": "active"}}`), nil
+		}
+
+		t.Errorf("unexpected request to %s", req.URL)
+		return newFakeResponse(req, http.StatusNotFound, ""), nil
+	}
+}
+
+func Test_Session(t *testing.T) {
+	newClient := func() *monstercat.Client {
+		return monstercat.NewClient(&http.Client{Transport: newAccountTransport(t)})
+	}
+
+	t.Run("with login and logout", func(t *testing.T) {
+		c := newClient()
+		_, err := c.Me(context.Background())
+		assert.ErrorIs(t, err, monstercat.ErrUnauthorized)
+
+		assert.NoError(t, c.Login(context.Background(), "me@example.com", "secret"))
+		account, err := c.Me(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "user", account.ID)
+		assert.True(t, account.HasGold)
+		assert.Equal(t, "active", account.Subscription.Status)
+
+		assert.NoError(t, c.Logout(context.Background()))
+		_, err = c.Me(context.Background())
+		assert.ErrorIs(t, err, monstercat.ErrUnauthorized)
+	})
+
+	t.Run("with invalid credentials", func(t *testing.T) {
+		err := newClient().Login(context.Background(), "me@example.com", "wrong")
+		assert.ErrorIs(t, err, monstercat.ErrUnauthorized)
+	})
+
+	t.Run("with two factor", func(t *testing.T) {
+		c := newClient()
+		err := c.Login(context.Background(), "2fa@example.com", "secret")
+		assert.ErrorIs(t, err, monstercat.ErrTwoFactorRequired)
+
+		var twoFactor *monstercat.TwoFactorError
+		assert.True(t, errors.As(err, &twoFactor))
+		assert.Equal(t, monstercat.TwoFactorTOTP, twoFactor.Method)
+
+		assert.ErrorContains(t, c.VerifyTwoFactor(context.Background(), twoFactor.Method, "000000"), "invalid code")
+		assert.NoError(t, c.VerifyTwoFactor(context.Background(), twoFactor.Method, "123456"))
+		_, err = c.Me(context.Background())
+		assert.NoError(t, err)
+	})
+
+	t.Run("with persisted session", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "session.json")
+		c := newClient()
+		assert.NoError(t, c.Login(context.Background(), "me@example.com", "secret"))
+		assert.NoError(t, c.SaveSession(path))
+
+		restored := newClient()
+		assert.NoError(t, restored.LoadSession(path))
+		account, err := restored.Me(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "me@example.com", account.Email)
+	})
+}
+
+func Test_DownloadTrackFile(t *testing.T) {
+	c := monstercat.NewClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if !strings.Contains(req.URL.Path, "/track-download/") {
+				return newAccountTransport(t)(req)
+			}
+			if cookie, err := req.Cookie("cid"); err != nil || cookie.Value != "session" {
+				return newFakeResponse(req, http.StatusUnauthorized, ""), nil
+			}
+			return newFakeResponse(req, http.StatusOK, "fLaC-"+req.URL.Query().Get("format")), nil
+		}),
+	})
+	track := monstercat.Track{ID: "track", Downloadable: true, Release: monstercat.Release{ID: "release"}}
+
+	t.Run("without session", func(t *testing.T) {
+		_, err := c.DownloadTrackFile(context.Background(), track, monstercat.DownloadFLAC)
+		assert.ErrorIs(t, err, monstercat.ErrUnauthorized)
+	})
+
+	t.Run("with session", func(t *testing.T) {
+		assert.NoError(t, c.Login(context.Background(), "me@example.com", "secret"))
+		file, err := c.DownloadTrackFile(context.Background(), track, monstercat.DownloadFLAC)
+		assert.NoError(t, err)
+		defer file.Close()
+
+		buf, err := io.ReadAll(file)
+		assert.NoError(t, err)
+		assert.Equal(t, "fLaC-flac", string(buf))
+	})
+
+	t.Run("with track that is not downloadable", func(t *testing.T) {
+		streamOnly := track
+		streamOnly.Downloadable = false
+		_, err := c.DownloadTrackFile(context.Background(), streamOnly, monstercat.DownloadWAV)
+		assert.ErrorIs(t, err, monstercat.ErrNotDownloadable)
+	})
+
+	t.Run("with invalid format", func(t *testing.T) {
+		_, err := c.DownloadTrackFile(context.Background(), track, monstercat.DownloadFormat("ogg"))
+		assert.ErrorContains(t, err, "invalid download format")
+	})
+}
+
+func Test_DownloadRelease(t *testing.T) {
+	newZip := func(t *testing.T, files map[string]string) []byte {
+		buf := new(bytes.Buffer)
+		zw := zip.NewWriter(buf)
+		for name, content := range files {
+			w, err := zw.Create(name)
+			assert.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, zw.Close())
+		return buf.Bytes()
+	}
+
+	newClient := func(archive []byte) *monstercat.Client {
+		c := monstercat.NewClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if !strings.HasSuffix(req.URL.Path, "/download") {
+					return newAccountTransport(t)(req)
+				}
+				if cookie, err := req.Cookie("cid"); err != nil || cookie.Value != "session" {
+					return newFakeResponse(req, http.StatusUnauthorized, ""), nil
+				}
+				return newFakeResponse(req, http.StatusOK, string(archive)), nil
+			}),
+		})
+		return c
+	}
+
+	release := monstercat.Release{ID: "release"}
+	files := map[string]string{
+		"01 - Intro.flac":   strings.Repeat("intro", 1000),
+		"02 - Outro.flac":   strings.Repeat("outro", 1000),
+		"artwork/cover.jpg": "cover",
+	}
+	archive := newZip(t, files)
+
+	t.Run("without session", func(t *testing.T) {
+		_, err := newClient(archive).DownloadRelease(context.Background(), release, monstercat.DownloadFLAC, io.Discard)
+		assert.ErrorIs(t, err, monstercat.ErrUnauthorized)
+	})
+
+	t.Run("with writer", func(t *testing.T) {
+		c := newClient(archive)
+		assert.NoError(t, c.Login(context.Background(), "me@example.com", "secret"))
+
+		buf := new(bytes.Buffer)
+		n, err := c.DownloadRelease(context.Background(), release, monstercat.DownloadFLAC, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len(archive)), n)
+		assert.Equal(t, archive, buf.Bytes())
+	})
+
+	t.Run("with directory", func(t *testing.T) {
+		c := newClient(archive)
+		assert.NoError(t, c.Login(context.Background(), "me@example.com", "secret"))
+
+		dir := t.TempDir()
+		paths, err := c.DownloadReleaseToDir(context.Background(), release, monstercat.DownloadFLAC, dir)
+		assert.NoError(t, err)
+		assert.Len(t, paths, len(files))
+
+		for name, content := range files {
+			b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+			assert.NoError(t, err)
+			assert.Equal(t, content, string(b))
+		}
+	})
+
+	t.Run("with corrupted entry", func(t *testing.T) {
+		corrupted := newZip(t, map[string]string{"track.flac": "audio"})
+		// flip the stored crc in the data descriptor that follows the entry
+		i := bytes.Index(corrupted, []byte{0x50, 0x4b, 0x07, 0x08})
+		corrupted[i+4] ^= 0xFF
+
+		c := newClient(corrupted)
+		assert.NoError(t, c.Login(context.Background(), "me@example.com", "secret"))
+
+		dir := t.TempDir()
+		_, err := c.DownloadReleaseToDir(context.Background(), release, monstercat.DownloadFLAC, dir)
+		assert.ErrorContains(t, err, "failed verification")
+
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("with entry outside directory", func(t *testing.T) {
+		c := newClient(newZip(t, map[string]string{"../evil.flac": "audio"}))
+		assert.NoError(t, c.Login(context.Background(), "me@example.com", "secret"))
+
+		_, err := c.DownloadReleaseToDir(context.Background(), release, monstercat.DownloadFLAC, t.TempDir())
+		assert.ErrorContains(t, err, "outside the target directory")
+	})
+}
+
+// newPlaylistTransport fakes an in-memory playlist store for a signed in account.
+func newPlaylistTransport(t *testing.T) roundTripFunc {
+	type item struct {
+		ReleaseID string `json:"ReleaseId"`
+		TrackID   string `json:"TrackId"`
+	}
+	playlists := make(map[string][]item)
+	titles := make(map[string]string)
+	account := newAccountTransport(t)
+
+	return func(req *http.Request) (*http.Response, error) {
+		if !strings.HasPrefix(req.URL.Path, "/api/playlist") {
+			return account(req)
+		}
+		if cookie, err := req.Cookie("cid"); err != nil || cookie.Value != "session" {
+			return newFakeResponse(req, http.StatusUnauthorized, ""), nil
+		}
+
+		parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/api/"), "/")
+		switch {
+		case parts[0] == "playlists":
+			data := make([]string, 0)
+			for id, title := range titles {
+				data = append(data, fmt.Sprintf(`{"Id": %q, "Title": %q, "NumRecords": %d}`, id, title, len(playlists[id])))
+			}
+			return newFakeResponse(req, http.StatusOK, fmt.Sprintf(`{"Total": %d, "Data": [%s]}`, len(data), strings.Join(data, ","))), nil
+		case len(parts) == 1 && req.Method == http.MethodPost:
+			body := make(map[string]any)
+			json.NewDecoder(req.Body).Decode(&body)
+			id := fmt.Sprintf("playlist-%d", len(titles)+1)
+			titles[id] = body["Title"].(string)
+			playlists[id] = []item{}
+			return newFakeResponse(req, http.StatusOK, fmt.Sprintf(`{"Playlist": {"Id": %q, "Title": %q}}`, id, titles[id])), nil
+		}
+
+		id := parts[1]
+		if _, ok := titles[id]; !ok {
+			return newFakeResponse(req, http.StatusNotFound, `{"Message": "playlist not found"}`), nil
+		}
+
+		if len(parts) == 2 && req.Method == http.MethodDelete {
+			delete(titles, id)
+			delete(playlists, id)
+			return newFakeResponse(req, http.StatusOK, ""), nil
+		}
+		if len(parts) == 2 {
+			return newFakeResponse(req, http.StatusOK, fmt.Sprintf(`{"Playlist": {"Id": %q, "Title": %q, "NumRecords": %d}}`, id, titles[id], len(playlists[id]))), nil
+		}
+
+		if parts[2] == "catalog" {
+			limit, _ := strconv.Atoi(req.URL.Query().Get("limit"))
+			offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+			items := playlists[id]
+			end := min(offset+limit, len(items))
+			data := make([]string, 0)
+			for _, it := range items[min(offset, end):end] {
+				data = append(data, fmt.Sprintf(`{"Id": %q, "Release": {"Id": %q}}`, it.TrackID, it.ReleaseID))
+			}
+			return newFakeResponse(req, http.StatusOK, fmt.Sprintf(`{"Limit": %d, "Offset": %d, "Total": %d, "Data": [%s]}`, limit, offset, len(items), strings.Join(data, ","))), nil
+		}
+
+		body := struct{ Records []item }{}
+		json.NewDecoder(req.Body).Decode(&body)
+		switch parts[2] {
+		case "add-items":
+			playlists[id] = append(playlists[id], body.Records...)
+		case "reorder-items":
+			playlists[id] = body.Records
+		case "remove-items":
+			kept := make([]item, 0)
+			for _, it := range playlists[id] {
+				removed := false
+				for _, r := range body.Records {
+					removed = removed || r == it
+				}
+				if !removed {
+					kept = append(kept, it)
+				}
+			}
+			playlists[id] = kept
+		}
+		return newFakeResponse(req, http.StatusOK, ""), nil
+	}
+}
+
+func Test_Playlists(t *testing.T) {
+	c := monstercat.NewClient(&http.Client{Transport: newPlaylistTransport(t)})
+	ctx := context.Background()
+
+	tracks := make([]monstercat.Track, 0)
+	for _, id := range []string{"a", "b", "c"} {
+		tracks = append(tracks, monstercat.Track{ID: id, Release: monstercat.Release{ID: "release"}})
+	}
+	trackIDs := func(tracks []monstercat.Track) []string {
+		ids := make([]string, 0)
+		for _, track := range tracks {
+			ids = append(ids, track.ID)
+		}
+		return ids
+	}
+
+	t.Run("without session", func(t *testing.T) {
+		_, err := c.ListPlaylists(ctx)
+		assert.ErrorIs(t, err, monstercat.ErrUnauthorized)
+	})
+
+	t.Run("with session", func(t *testing.T) {
+		assert.NoError(t, c.Login(ctx, "me@example.com", "secret"))
+
+		playlist, err := c.CreatePlaylist(ctx, "Gaming", "", false)
+		assert.NoError(t, err)
+		assert.Equal(t, "Gaming", playlist.Title)
+
+		assert.NoError(t, c.AddTracks(ctx, playlist.ID, tracks...))
+
+		info, err := c.GetPlaylist(ctx, playlist.ID, monstercat.WithLimit(2))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, info.NumTracks)
+		assert.Equal(t, []string{"a", "b"}, trackIDs(info.Tracks.Tracks))
+		assert.True(t, info.Tracks.HasNext)
+
+		next, err := info.Tracks.Next(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c"}, trackIDs(next.Tracks))
+		assert.False(t, next.HasNext)
+
+		assert.NoError(t, c.ReorderTracks(ctx, playlist.ID, []monstercat.Track{tracks[2], tracks[0], tracks[1]}))
+		assert.NoError(t, c.RemoveTracks(ctx, playlist.ID, tracks[0]))
+		info, err = c.GetPlaylist(ctx, playlist.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"c", "b"}, trackIDs(info.Tracks.Tracks))
+
+		playlists, err := c.ListPlaylists(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, playlists, 1)
+
+		assert.NoError(t, c.DeletePlaylist(ctx, playlist.ID))
+		_, err = c.GetPlaylist(ctx, playlist.ID)
+		assert.ErrorContains(t, err, "playlist not found")
+	})
+}
+
+func Test_Browse(t *testing.T) {
+	c := monstercat.NewClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			switch req.URL.Path {
+			case "/api/moods":
+				return newFakeResponse(req, http.StatusOK, `{"Moods": {"Data": [{"Id": "1", "URI": "chill", "Name": "Chill"}, {"Id": "2", "URI": "hype", "Name": "Hype"}]}}`), nil
+			case "/api/mood/chill":
+				return newFakeResponse(req, http.StatusOK, `{"Mood": {"Id": "1", "URI": "chill", "Name": "Chill"}}`), nil
+			case "/api/mood/chill/catalog":
+				return newFakeResponse(req, http.StatusOK, `{"Limit": 1, "Offset": 0, "Total": 2, "Data": [{"Id": "track", "Release": {"Id": "release"}}]}`), nil
+			case "/api/playlists/public":
+				if req.URL.Query().Get("offset") == "0" {
+					return newFakeResponse(req, http.StatusOK, `{"Total": 2, "Data": [{"Id": "playlist", "Title": "Best of 2024", "Public": true}]}`), nil
+				}
+				return newFakeResponse(req, http.StatusOK, `{"Total": 2, "Data": [{"Id": "editorial", "Title": "Best of 2025", "Public": true}]}`), nil
+			case "/api/catalog/filters":
+				return newFakeResponse(req, http.StatusOK, `{"Genres": [{"Name": "Drum & Bass", "Count": 120}, {"Name": "Dubstep", "Count": 80}]}`), nil
+			}
+			return newFakeResponse(req, http.StatusNotFound, `{"Message": "not found"}`), nil
+		}),
+	})
+	ctx := context.Background()
+
+	t.Run("with moods", func(t *testing.T) {
+		moods, err := c.ListMoods(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, moods, 2)
+		assert.NotEmpty(t, moods[0].CoverURL)
+
+		mood, err := c.GetMood(ctx, "chill", monstercat.WithLimit(1))
+		assert.NoError(t, err)
+		assert.Equal(t, "Chill", mood.Name)
+		assert.Len(t, mood.Tracks.Tracks, 1)
+		assert.True(t, mood.Tracks.HasNext)
+
+		_, err = c.GetMood(ctx, "missing")
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("with public playlists", func(t *testing.T) {
+		playlists, err := c.ListPublicPlaylists(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, playlists, 2)
+		assert.True(t, playlists[0].Public)
+		assert.Equal(t, "editorial", playlists[1].ID)
+	})
+
+	t.Run("with genres", func(t *testing.T) {
+		genres, err := c.ListGenres(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []monstercat.Genre{{Name: "Drum & Bass", Count: 120}, {Name: "Dubstep", Count: 80}}, genres)
+	})
+}
+
+func Test_Licenses(t *testing.T) {
+	licenses := make([]string, 0)
+	account := newAccountTransport(t)
+	c := monstercat.NewClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, "/api/self/licenses") {
+				return account(req)
+			}
+			if cookie, err := req.Cookie("cid"); err != nil || cookie.Value != "session" {
+				return newFakeResponse(req, http.StatusUnauthorized, ""), nil
+			}
+
+			switch req.Method {
+			case http.MethodPost:
+				body := make(map[string]string)
+				json.NewDecoder(req.Body).Decode(&body)
+				license := fmt.Sprintf(`{"Id": "%d", "Vendor": %q, "Identity": %q, "State": "Active"}`, len(licenses)+1, body["Vendor"], body["Identity"])
+				licenses = append(licenses, license)
+				return newFakeResponse(req, http.StatusOK, license), nil
+			case http.MethodDelete:
+				licenses = licenses[:0]
+				return newFakeResponse(req, http.StatusOK, ""), nil
+			}
+			return newFakeResponse(req, http.StatusOK, fmt.Sprintf(`{"Data": [%s]}`, strings.Join(licenses, ","))), nil
+		}),
+	})
+	ctx := context.Background()
+	track := monstercat.Track{ID: "track", CreatorFriendly: true}
+
+	t.Run("without session", func(t *testing.T) {
+		_, err := c.ListLicenses(ctx)
+		assert.ErrorIs(t, err, monstercat.ErrUnauthorized)
+	})
+
+	t.Run("with session", func(t *testing.T) {
+		assert.NoError(t, c.Login(ctx, "me@example.com", "secret"))
+
+		cleared, err := c.CheckTrackCleared(ctx, track, monstercat.LicenseTwitch)
+		assert.NoError(t, err)
+		assert.False(t, cleared)
+
+		license, err := c.AddLicense(ctx, "https://www.twitch.tv/monstercat")
+		assert.NoError(t, err)
+		assert.Equal(t, monstercat.LicenseTwitch, license.Platform)
+		assert.True(t, license.Active)
+
+		cleared, err = c.CheckTrackCleared(ctx, track, monstercat.LicenseTwitch)
+		assert.NoError(t, err)
+		assert.True(t, cleared)
+
+		cleared, err = c.CheckTrackCleared(ctx, track, monstercat.LicenseYouTube)
+		assert.NoError(t, err)
+		assert.False(t, cleared)
+
+		notFriendly := track
+		notFriendly.CreatorFriendly = false
+		cleared, err = c.CheckTrackCleared(ctx, notFriendly, monstercat.LicenseTwitch)
+		assert.NoError(t, err)
+		assert.False(t, cleared)
+
+		assert.NoError(t, c.RemoveLicense(ctx, license.ID))
+		all, err := c.ListLicenses(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("with invalid channel url", func(t *testing.T) {
+		for _, u := range []string{"", "https://example.com/channel", "https://www.youtube.com/"} {
+			_, err := c.AddLicense(ctx, u)
+			assert.Error(t, err)
+		}
+	})
+}
+
+func Test_Library(t *testing.T) {
+	liked := make([]string, 0)
+	account := newAccountTransport(t)
+	c := monstercat.NewClient(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, "/api/self/") {
+				return account(req)
+			}
+			if cookie, err := req.Cookie("cid"); err != nil || cookie.Value != "session" {
+				return newFakeResponse(req, http.StatusUnauthorized, ""), nil
+			}
+
+			if req.URL.Path == "/api/self/following" {
+				return newFakeResponse(req, http.StatusOK, `{"Data": [{"Id": "artist", "Name": "Nitro Fun", "URI": "nitrofun"}]}`), nil
+			}
+
+			body := make(map[string]string)
+			if req.Body != nil {
+				json.NewDecoder(req.Body).Decode(&body)
+			}
+			switch req.Method {
+			case http.MethodPost:
+				liked = append(liked, fmt.Sprintf(`{"Id": %q, "Title": "Liked", "Artists": [{"Name": "Nitro Fun"}], "Release": {"Id": %q}}`, body["TrackId"], body["ReleaseId"]))
+			case http.MethodDelete:
+				liked = liked[:0]
+			default:
+				return newFakeResponse(req, http.StatusOK, fmt.Sprintf(`{"Limit": 100, "Offset": 0, "Total": %d, "Data": [%s]}`, len(liked), strings.Join(liked, ","))), nil
+			}
+			return newFakeResponse(req, http.StatusOK, ""), nil
+		}),
+	})
+	ctx := context.Background()
+	track := monstercat.Track{ID: "track", Release: monstercat.Release{ID: "release"}}
+
+	t.Run("without session", func(t *testing.T) {
+		_, err := c.ListLikedTracks(ctx)
+		assert.ErrorIs(t, err, monstercat.ErrUnauthorized)
+	})
+
+	t.Run("with session", func(t *testing.T) {
+		assert.NoError(t, c.Login(ctx, "me@example.com", "secret"))
+		assert.NoError(t, c.LikeTrack(ctx, track))
+
+		page, err := c.ListLikedTracks(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, page.Tracks, 1)
+		assert.Equal(t, "track", page.Tracks[0].ID)
+		assert.Equal(t, "release", page.Tracks[0].Release.ID)
+		assert.Equal(t, "Nitro Fun", page.Tracks[0].Artists[0].Name)
+		assert.False(t, page.HasNext)
+
+		assert.NoError(t, c.UnlikeTrack(ctx, track))
+		page, err = c.ListLikedTracks(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, page.Tracks)
+
+		artists, err := c.ListFollowedArtists(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, artists, 1)
+		assert.NotEmpty(t, artists[0].ProfileImageURL)
+	})
+}
+
+func Test_JSON(t *testing.T) {
+	debut := time.Date(2019, 4, 2, 16, 0, 0, 0, time.UTC)
+	track := monstercat.Track{
+		ID:          "track",
+		Title:       "Emoji",
+		DebutDate:   debut,
+		BPM:         128,
+		Explicit:    true,
+		Release:     monstercat.Release{ID: "release", CatalogID: "MCS123", ReleaseDate: debut},
+		Artists:     []monstercat.Artist{{Name: "Pegboard Nerds", URI: "pegboardnerds"}},
+		TrackNumber: 1,
+	}
+
+	t.Run("with camel case keys and rfc 3339 times", func(t *testing.T) {
+		b, err := json.Marshal(track)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"id": "track", "title": "Emoji", "debutDate": "2019-04-02T16:00:00Z", "bpm": 128, "explicit": true, "trackNumber": 1,
+			"release": {"id": "release", "catalogId": "MCS123", "releaseDate": "2019-04-02T16:00:00Z"},
+			"artists": [{"name": "Pegboard Nerds", "uri": "pegboardnerds"}]
+		}`, string(b))
+	})
+
+	t.Run("with empty fields left out", func(t *testing.T) {
+		for _, v := range []any{monstercat.Track{}, monstercat.Release{}, monstercat.ReleaseInfo{}, monstercat.Artist{}, monstercat.SearchCatalogResults{}} {
+			b, err := json.Marshal(v)
+			assert.NoError(t, err)
+			assert.Equal(t, "{}", string(b))
+		}
+	})
+
+	t.Run("with round trip", func(t *testing.T) {
+		info := monstercat.ReleaseInfo{ID: "release", Title: "Emoji", ReleaseDate: debut, Tracks: []monstercat.Track{track}}
+		b, err := json.Marshal(info)
+		assert.NoError(t, err)
+
+		var decoded monstercat.ReleaseInfo
+		assert.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, info, decoded)
+
+		again, err := json.Marshal(decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, string(b), string(again))
+	})
+
+	t.Run("with next on decoded results", func(t *testing.T) {
+		var res monstercat.SearchCatalogResults
+		assert.NoError(t, json.Unmarshal([]byte(`{"limit": 1, "total": 2, "tracks": [{"id": "track"}], "hasNext": true}`), &res))
+		assert.Equal(t, "track", res.Tracks[0].ID)
+
+		_, err := res.Next(context.Background())
+		assert.EqualError(t, err, "results are not attached to a client")
+	})
+}
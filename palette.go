@@ -0,0 +1,217 @@
+package monstercat
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"sort"
+	"sync"
+)
+
+// width the cover is fetched at for palette extraction, plenty for dominant colors.
+const paletteCoverWidth = 64
+
+// CoverPalette returns at most n dominant colors of the release cover, most dominant first.
+func (c *Client) CoverPalette(ctx context.Context, release Release, n int) ([]color.RGBA, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("number of colors must be positive")
+	}
+
+	cover, err := c.GetCoverImage(ctx, release, WithWidth(paletteCoverWidth), WithEncoding(JPEG))
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(cover.Data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding cover: %w", err)
+	}
+
+	return medianCut(img, n), nil
+}
+
+// Palette Cache.
+//
+// Caches cover palettes by release catalog id.
+type PaletteCache struct {
+	c *Client
+
+	mu      sync.Mutex
+	entries map[string][]color.RGBA
+}
+
+// NewPaletteCache returns an empty palette cache.
+func NewPaletteCache(c *Client) *PaletteCache {
+	return &PaletteCache{
+		c:       c,
+		entries: make(map[string][]color.RGBA),
+	}
+}
+
+// Get returns the cached palette for the release, extracting it on first use.
+func (cache *PaletteCache) Get(ctx context.Context, release Release, n int) ([]color.RGBA, error) {
+	if len(release.CatalogID) == 0 {
+		return nil, fmt.Errorf("catalog id is empty for release")
+	}
+
+	key := fmt.Sprintf("%s/%d", release.CatalogID, n)
+
+	cache.mu.Lock()
+	palette, ok := cache.entries[key]
+	cache.mu.Unlock()
+	if ok {
+		return append([]color.RGBA(nil), palette...), nil
+	}
+
+	palette, err := cache.c.CoverPalette(ctx, release, n)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	cache.entries[key] = palette
+	cache.mu.Unlock()
+
+	return append([]color.RGBA(nil), palette...), nil
+}
+
+type colorBox struct {
+	pixels [][3]uint8
+}
+
+// channel returns the channel with the widest range in the box and that range.
+func (b *colorBox) channel() (int, int) {
+	widest, widestRange := 0, -1
+	for ch := 0; ch < 3; ch++ {
+		lo, hi := 255, 0
+		for _, p := range b.pixels {
+			lo = min(lo, int(p[ch]))
+			hi = max(hi, int(p[ch]))
+		}
+		if hi-lo > widestRange {
+			widest, widestRange = ch, hi-lo
+		}
+	}
+	return widest, widestRange
+}
+
+func (b *colorBox) average() color.RGBA {
+	var sum [3]int
+	for _, p := range b.pixels {
+		for ch := 0; ch < 3; ch++ {
+			sum[ch] += int(p[ch])
+		}
+	}
+
+	count := len(b.pixels)
+	return color.RGBA{
+		R: uint8(sum[0] / count),
+		G: uint8(sum[1] / count),
+		B: uint8(sum[2] / count),
+		A: 0xFF,
+	}
+}
+
+// medianCut quantizes img into at most n colors, ordered by how many pixels they cover.
+func medianCut(img image.Image, n int) []color.RGBA {
+	bounds := img.Bounds()
+	pixels := make([][3]uint8, 0, bounds.Dx()*bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			pixels = append(pixels, [3]uint8{c.R, c.G, c.B})
+		}
+	}
+	if len(pixels) == 0 {
+		return []color.RGBA{}
+	}
+
+	boxes := []*colorBox{{pixels: pixels}}
+	for len(boxes) < n {
+		// split the box with the widest channel range.
+		split, splitCh, splitRange := -1, 0, 0
+		for i, b := range boxes {
+			if len(b.pixels) < 2 {
+				continue
+			}
+			if ch, r := b.channel(); r > splitRange {
+				split, splitCh, splitRange = i, ch, r
+			}
+		}
+		if split < 0 {
+			break
+		}
+
+		b := boxes[split]
+		sort.Slice(b.pixels, func(i, j int) bool {
+			return b.pixels[i][splitCh] < b.pixels[j][splitCh]
+		})
+		mid := len(b.pixels) / 2
+		boxes[split] = &colorBox{pixels: b.pixels[:mid]}
+		boxes = append(boxes, &colorBox{pixels: b.pixels[mid:]})
+	}
+
+	centers := make([]color.RGBA, 0, len(boxes))
+	for _, b := range boxes {
+		centers = append(centers, b.average())
+	}
+
+	return refinePalette(pixels, centers)
+}
+
+// refinePalette runs a few k-means rounds from the median cut colors, which
+// fixes boxes straddling two colors, and orders the result by population.
+func refinePalette(pixels [][3]uint8, centers []color.RGBA) []color.RGBA {
+	counts := make([]int, len(centers))
+	for round := 0; round < 8; round++ {
+		sums := make([][3]int, len(centers))
+		for i := range counts {
+			counts[i] = 0
+		}
+
+		for _, p := range pixels {
+			nearest, nearestDist := 0, -1
+			for i, c := range centers {
+				dr, dg, db := int(p[0])-int(c.R), int(p[1])-int(c.G), int(p[2])-int(c.B)
+				if d := dr*dr + dg*dg + db*db; nearestDist < 0 || d < nearestDist {
+					nearest, nearestDist = i, d
+				}
+			}
+			counts[nearest]++
+			for ch := 0; ch < 3; ch++ {
+				sums[nearest][ch] += int(p[ch])
+			}
+		}
+
+		for i := range centers {
+			if counts[i] == 0 {
+				continue
+			}
+			centers[i] = color.RGBA{
+				R: uint8(sums[i][0] / counts[i]),
+				G: uint8(sums[i][1] / counts[i]),
+				B: uint8(sums[i][2] / counts[i]),
+				A: 0xFF,
+			}
+		}
+	}
+
+	order := make([]int, len(centers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+
+	palette := make([]color.RGBA, 0, len(centers))
+	for _, i := range order {
+		if counts[i] > 0 {
+			palette = append(palette, centers[i])
+		}
+	}
+	return palette
+}
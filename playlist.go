@@ -0,0 +1,232 @@
+package monstercat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Playlist.
+type Playlist struct {
+	ID          string
+	Title       string
+	Description string
+	Public      bool
+	NumTracks   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Playlist Info.
+type PlaylistInfo struct {
+	ID          string
+	Title       string
+	Description string
+	Public      bool
+	NumTracks   int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Tracks      TrackPage
+}
+
+// Playlist API Response.
+type playlistAPIResponse struct {
+	ID          string    `json:"Id"`
+	Title       string    `json:"Title"`
+	Description string    `json:"Description"`
+	Public      bool      `json:"Public"`
+	NumRecords  int       `json:"NumRecords"`
+	CreatedAt   time.Time `json:"CreatedAt"`
+	UpdatedAt   time.Time `json:"UpdatedAt"`
+}
+
+// List Playlists API Response.
+type listPlaylistsAPIResponse struct {
+	Limit  int                   `json:"Limit"`
+	Offset int                   `json:"Offset"`
+	Total  int                   `json:"Total"`
+	Data   []playlistAPIResponse `json:"Data"`
+}
+
+// Get Playlist API Response.
+type getPlaylistAPIResponse struct {
+	Playlist playlistAPIResponse `json:"Playlist"`
+}
+
+// playlist item as sent when editing a playlist.
+type playlistItem struct {
+	ReleaseID string `json:"ReleaseId"`
+	TrackID   string `json:"TrackId"`
+}
+
+func (r *playlistAPIResponse) toPlaylist() Playlist {
+	return Playlist{
+		ID:          r.ID,
+		Title:       r.Title,
+		Description: r.Description,
+		Public:      r.Public,
+		NumTracks:   r.NumRecords,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+// ListPlaylists returns every playlist of the signed in account.
+func (c *Client) ListPlaylists(ctx context.Context) ([]Playlist, error) {
+	return c.listPlaylists(ctx, "playlists")
+}
+
+// listPlaylists walks every page of the playlists at path.
+func (c *Client) listPlaylists(ctx context.Context, path string) ([]Playlist, error) {
+	playlists := make([]Playlist, 0)
+	opts := newOptions()
+
+	for {
+		params := make(map[string]string)
+		params["limit"] = strconv.Itoa(opts.limit)
+		params["offset"] = strconv.Itoa(opts.offset)
+
+		req, err := c.makeRequest(ctx, path, params)
+		if err != nil {
+			return nil, err
+		}
+
+		apiResponse := new(listPlaylistsAPIResponse)
+		if err := c.doJSON(req, apiResponse); err != nil {
+			return nil, err
+		}
+
+		for _, result := range apiResponse.Data {
+			playlists = append(playlists, result.toPlaylist())
+		}
+
+		opts.offset += len(apiResponse.Data)
+		if len(apiResponse.Data) == 0 || opts.offset >= apiResponse.Total {
+			return playlists, nil
+		}
+	}
+}
+
+// GetPlaylist returns the playlist with the first page of its tracks, WithLimit and WithOffset control the page.
+func (c *Client) GetPlaylist(ctx context.Context, id string, opts ...Option) (PlaylistInfo, error) {
+	id = strings.TrimSpace(id)
+	if len(id) == 0 {
+		return PlaylistInfo{}, fmt.Errorf("id cannot be empty")
+	}
+
+	options := newOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	req, err := c.makeRequest(ctx, fmt.Sprintf("playlist/%s", id), nil)
+	if err != nil {
+		return PlaylistInfo{}, err
+	}
+
+	apiResponse := new(getPlaylistAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return PlaylistInfo{}, err
+	}
+
+	tracks, err := c.getTrackPage(ctx, fmt.Sprintf("playlist/%s/catalog", id), options)
+	if err != nil {
+		return PlaylistInfo{}, fmt.Errorf("error while getting tracks for playlist: %w", err)
+	}
+
+	p := apiResponse.Playlist
+	return PlaylistInfo{
+		ID:          p.ID,
+		Title:       p.Title,
+		Description: p.Description,
+		Public:      p.Public,
+		NumTracks:   p.NumRecords,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		Tracks:      tracks,
+	}, nil
+}
+
+// CreatePlaylist creates a playlist on the signed in account.
+func (c *Client) CreatePlaylist(ctx context.Context, title string, description string, public bool) (Playlist, error) {
+	title = strings.TrimSpace(title)
+	if len(title) == 0 {
+		return Playlist{}, fmt.Errorf("title cannot be empty")
+	}
+
+	body := map[string]any{
+		"Title":       title,
+		"Description": description,
+		"Public":      public,
+	}
+	req, err := c.makeJSONRequest(ctx, http.MethodPost, "playlist", body)
+	if err != nil {
+		return Playlist{}, err
+	}
+
+	apiResponse := new(getPlaylistAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return Playlist{}, err
+	}
+
+	return apiResponse.Playlist.toPlaylist(), nil
+}
+
+// AddTracks appends the tracks to the playlist.
+func (c *Client) AddTracks(ctx context.Context, id string, tracks ...Track) error {
+	return c.editPlaylist(ctx, id, "add-items", tracks)
+}
+
+// RemoveTracks removes the tracks from the playlist.
+func (c *Client) RemoveTracks(ctx context.Context, id string, tracks ...Track) error {
+	return c.editPlaylist(ctx, id, "remove-items", tracks)
+}
+
+// ReorderTracks sets the order of the playlist to the order of tracks, which must hold every track in it.
+func (c *Client) ReorderTracks(ctx context.Context, id string, tracks []Track) error {
+	return c.editPlaylist(ctx, id, "reorder-items", tracks)
+}
+
+// DeletePlaylist deletes the playlist.
+func (c *Client) DeletePlaylist(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if len(id) == 0 {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	req, err := c.makeJSONRequest(ctx, http.MethodDelete, fmt.Sprintf("playlist/%s", id), nil)
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(req, nil)
+}
+
+func (c *Client) editPlaylist(ctx context.Context, id string, action string, tracks []Track) error {
+	id = strings.TrimSpace(id)
+	if len(id) == 0 {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	if len(tracks) == 0 {
+		return fmt.Errorf("tracks cannot be empty")
+	}
+
+	items := make([]playlistItem, 0, len(tracks))
+	for _, track := range tracks {
+		if len(track.ID) == 0 || len(track.Release.ID) == 0 {
+			return fmt.Errorf("track and release id are required for playlist tracks")
+		}
+		items = append(items, playlistItem{ReleaseID: track.Release.ID, TrackID: track.ID})
+	}
+
+	req, err := c.makeJSONRequest(ctx, http.MethodPost, fmt.Sprintf("playlist/%s/%s", id, action), map[string]any{"Records": items})
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(req, nil)
+}
@@ -0,0 +1,179 @@
+package monstercat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stream Proxy.
+//
+// An http.Handler serving /tracks/{releaseID}/{trackID} with Range support.
+// Tracks are fetched through GetTrackStreamURL on first request and kept in a
+// size bounded on-disk cache, so repeat plays are served locally. Tracks
+// larger than the cache are redirected to the signed url instead.
+type StreamProxy struct {
+	c     *Client
+	urls  *StreamURLCache
+	cache *diskCache
+
+	mu       sync.Mutex
+	inflight map[string]*proxyFetch
+}
+
+type proxyFetch struct {
+	done chan struct{}
+	url  string
+	err  error
+}
+
+// NewStreamProxy returns a stream proxy caching up to maxBytes of audio in dir.
+func NewStreamProxy(c *Client, dir string, maxBytes int64) (*StreamProxy, error) {
+	cache, err := newDiskCache(dir, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamProxy{
+		c:        c,
+		urls:     NewStreamURLCache(c, time.Minute),
+		cache:    cache,
+		inflight: make(map[string]*proxyFetch),
+	}, nil
+}
+
+func (p *StreamProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	track, ok := parseProxyPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	key := track.Release.ID + "/" + track.ID
+	f, contentType, ok := p.cache.open(key)
+	if !ok {
+		signedURL, err := p.fetch(r.Context(), key, track)
+		if errors.Is(err, errTooLargeForCache) {
+			http.Redirect(w, r, signedURL, http.StatusTemporaryRedirect)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		f, contentType, ok = p.cache.open(key)
+		if !ok {
+			http.Error(w, "track was evicted from cache", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if len(contentType) == 0 {
+		contentType = "audio/mpeg"
+	}
+	w.Header().Set("Content-Type", contentType)
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
+
+// fetch downloads the track into the cache once, however many requests ask for it.
+func (p *StreamProxy) fetch(ctx context.Context, key string, track Track) (string, error) {
+	p.mu.Lock()
+	call, ok := p.inflight[key]
+	if !ok {
+		call = &proxyFetch{done: make(chan struct{})}
+		p.inflight[key] = call
+	}
+	p.mu.Unlock()
+
+	if ok {
+		select {
+		case <-call.done:
+			return call.url, call.err
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	// keep downloading for the other waiters even if this request goes away.
+	call.url, call.err = p.download(context.WithoutCancel(ctx), key, track)
+	close(call.done)
+
+	p.mu.Lock()
+	delete(p.inflight, key)
+	p.mu.Unlock()
+
+	return call.url, call.err
+}
+
+func (p *StreamProxy) download(ctx context.Context, key string, track Track) (string, error) {
+	u, err := p.urls.Get(ctx, track)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.URL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("invalid track")
+	}
+
+	if resp.ContentLength > p.cache.maxBytes {
+		return u.URL, errTooLargeForCache
+	}
+
+	contentType := u.ContentType
+	if len(contentType) == 0 {
+		contentType = resp.Header.Get("Content-Type")
+	}
+	return u.URL, p.cache.put(key, resp.Body, contentType)
+}
+
+func parseProxyPath(path string) (Track, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 3 || parts[0] != "tracks" || !isProxyID(parts[1]) || !isProxyID(parts[2]) {
+		return Track{}, false
+	}
+
+	return Track{ID: parts[2], Release: Release{ID: parts[1]}}, true
+}
+
+// isProxyID reports whether id holds only the characters of the uuids used as
+// ids, so it cannot alter the api path it is put into.
+func isProxyID(id string) bool {
+	if len(id) == 0 {
+		return false
+	}
+	for _, r := range id {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,7 @@ package monstercat
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -21,11 +22,21 @@ func (t ReleaseType) String() string {
 
 // Release.
 type Release struct {
-	CatalogID string
-	ID        string
-	Title     string
-	Type      string
-	CoverURL  string
+	CatalogID   string    `json:"catalogId,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Type        string    `json:"type,omitempty"`
+	CoverURL    string    `json:"coverUrl,omitempty"`
+	ReleaseDate time.Time `json:"releaseDate"`
+}
+
+// MarshalJSON leaves out the release date when it is zero.
+func (r Release) MarshalJSON() ([]byte, error) {
+	type release Release
+	return json.Marshal(struct {
+		release
+		ReleaseDate *time.Time `json:"releaseDate,omitempty"`
+	}{release(r), timeOrNil(r.ReleaseDate)})
 }
 
 // Release API Response.
@@ -44,12 +55,30 @@ type releaseAPIResponse struct {
 
 // Release Info.
 type ReleaseInfo struct {
-	CatalogID string
-	ID        string
-	Title     string
-	Type      string
-	CoverURL  string
-	Tracks    []Track
+	CatalogID   string    `json:"catalogId,omitempty"`
+	ID          string    `json:"id,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Type        string    `json:"type,omitempty"`
+	CoverURL    string    `json:"coverUrl,omitempty"`
+	ReleaseDate time.Time `json:"releaseDate"`
+	Tracks      []Track   `json:"tracks,omitempty"`
+}
+
+// MarshalJSON leaves out the release date when it is zero.
+func (r ReleaseInfo) MarshalJSON() ([]byte, error) {
+	type releaseInfo ReleaseInfo
+	return json.Marshal(struct {
+		releaseInfo
+		ReleaseDate *time.Time `json:"releaseDate,omitempty"`
+	}{releaseInfo(r), timeOrNil(r.ReleaseDate)})
+}
+
+// timeOrNil returns nil for the zero time, which omitempty does not leave out on its own.
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
 }
 
 // Get Release API Response.
@@ -91,11 +120,12 @@ type getReleaseAPIResponse struct {
 
 func (r *releaseAPIResponse) toRelease() Release {
 	return Release{
-		CatalogID: r.CatalogID,
-		ID:        r.ID,
-		Title:     r.Title,
-		Type:      r.Type,
-		CoverURL:  buildReleaseCoverURL(r.CatalogID),
+		CatalogID:   r.CatalogID,
+		ID:          r.ID,
+		Title:       r.Title,
+		Type:        r.Type,
+		CoverURL:    buildReleaseCoverURL(r.CatalogID),
+		ReleaseDate: r.ReleaseDate,
 	}
 }
 
@@ -114,15 +144,28 @@ func (r *getReleaseAPIResponse) toReleaseInfo(ctx context.Context, c *Client) (R
 	}
 
 	return ReleaseInfo{
-		CatalogID: r.Release.CatalogID,
-		ID:        r.Release.ID,
-		Title:     r.Release.Title,
-		Type:      r.Release.Type,
-		CoverURL:  buildReleaseCoverURL(r.Release.CatalogID),
-		Tracks:    tracks,
+		CatalogID:   r.Release.CatalogID,
+		ID:          r.Release.ID,
+		Title:       r.Release.Title,
+		Type:        r.Release.Type,
+		CoverURL:    buildReleaseCoverURL(r.Release.CatalogID),
+		ReleaseDate: r.Release.ReleaseDate,
+		Tracks:      tracks,
 	}, nil
 }
 
 func buildReleaseCoverURL(catalogId string) string {
 	return fmt.Sprintf("%s/release/%s/cover", webURL, catalogId)
 }
+
+// Release returns the release without its tracks.
+func (r ReleaseInfo) Release() Release {
+	return Release{
+		CatalogID:   r.CatalogID,
+		ID:          r.ID,
+		Title:       r.Title,
+		Type:        r.Type,
+		CoverURL:    r.CoverURL,
+		ReleaseDate: r.ReleaseDate,
+	}
+}
@@ -0,0 +1,241 @@
+package monstercat
+
+import (
+	"bufio"
+	"compress/flate"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DownloadRelease writes the official ZIP bundle of the release in the
+// provided format to w. It needs a signed in session with download access.
+func (c *Client) DownloadRelease(ctx context.Context, release Release, format DownloadFormat, w io.Writer) (int64, error) {
+	body, err := c.getReleaseDownload(ctx, release, format)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	return io.Copy(w, body)
+}
+
+// DownloadReleaseToDir unpacks the ZIP bundle of the release into dir while
+// it downloads. Every entry is checked against its size and CRC-32 before it
+// is moved into place, and the paths of the unpacked files are returned.
+func (c *Client) DownloadReleaseToDir(ctx context.Context, release Release, format DownloadFormat, dir string) ([]string, error) {
+	body, err := c.getReleaseDownload(ctx, release, format)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return unzipStream(body, dir)
+}
+
+func (c *Client) getReleaseDownload(ctx context.Context, release Release, format DownloadFormat) (io.ReadCloser, error) {
+	if len(release.ID) == 0 {
+		return nil, fmt.Errorf("release id is empty for release")
+	}
+
+	if !isDownloadFormatAllowed(format) {
+		return nil, fmt.Errorf("invalid download format")
+	}
+
+	params := make(map[string]string)
+	params["format"] = format.String()
+
+	req, err := c.makeRequest(ctx, fmt.Sprintf("release/%s/download", release.ID), params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return newStreamBody(ctx, resp.Body), nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		resp.Body.Close()
+		return nil, ErrUnauthorized
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("invalid release")
+	}
+}
+
+const (
+	zipLocalHeaderSignature   = 0x04034b50
+	zipCentralHeaderSignature = 0x02014b50
+	zipDataDescriptorSig      = 0x08074b50
+	zipFlagDataDescriptor     = 0x8
+	zipMethodStore            = 0
+	zipMethodDeflate          = 8
+)
+
+// unzipStream unpacks the local file entries of a zip archive as they arrive,
+// stopping at the central directory.
+func unzipStream(r io.Reader, dir string) ([]string, error) {
+	// flate reads exactly up to the end of each entry through a ByteReader.
+	br := bufio.NewReader(r)
+	files := make([]string, 0)
+
+	for {
+		var sig uint32
+		if err := binary.Read(br, binary.LittleEndian, &sig); err != nil {
+			return files, fmt.Errorf("error reading zip entry: %w", err)
+		}
+
+		if sig == zipCentralHeaderSignature {
+			return files, nil
+		}
+		if sig != zipLocalHeaderSignature {
+			return files, fmt.Errorf("invalid zip entry signature")
+		}
+
+		path, err := unzipEntry(br, dir)
+		if err != nil {
+			return files, err
+		}
+		if len(path) != 0 {
+			files = append(files, path)
+		}
+	}
+}
+
+func unzipEntry(br *bufio.Reader, dir string) (string, error) {
+	var header struct {
+		Version          uint16
+		Flags            uint16
+		Method           uint16
+		ModTime          uint16
+		ModDate          uint16
+		CRC32            uint32
+		CompressedSize   uint32
+		UncompressedSize uint32
+		NameLength       uint16
+		ExtraLength      uint16
+	}
+	if err := binary.Read(br, binary.LittleEndian, &header); err != nil {
+		return "", fmt.Errorf("error reading zip entry: %w", err)
+	}
+
+	name := make([]byte, header.NameLength)
+	if _, err := io.ReadFull(br, name); err != nil {
+		return "", fmt.Errorf("error reading zip entry: %w", err)
+	}
+	if _, err := io.CopyN(io.Discard, br, int64(header.ExtraLength)); err != nil {
+		return "", fmt.Errorf("error reading zip entry: %w", err)
+	}
+
+	target, err := unzipTarget(dir, string(name))
+	if err != nil {
+		return "", err
+	}
+
+	hasDescriptor := header.Flags&zipFlagDataDescriptor != 0
+
+	var data io.Reader
+	switch header.Method {
+	case zipMethodDeflate:
+		data = flate.NewReader(br)
+	case zipMethodStore:
+		if hasDescriptor {
+			return "", fmt.Errorf("zip entry %s has no size and cannot be unpacked while downloading", name)
+		}
+		data = io.LimitReader(br, int64(header.CompressedSize))
+	default:
+		return "", fmt.Errorf("zip entry %s uses unsupported compression", name)
+	}
+
+	if strings.HasSuffix(string(name), "/") {
+		if _, err := io.Copy(io.Discard, data); err != nil {
+			return "", err
+		}
+		if hasDescriptor {
+			if _, _, err := readZipDataDescriptor(br); err != nil {
+				return "", err
+			}
+		}
+		return "", os.MkdirAll(target, 0o755)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".unzip-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := crc32.NewIEEE()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("error unpacking zip entry %s: %w", name, err)
+	}
+
+	crc, expectedSize := header.CRC32, int64(header.UncompressedSize)
+	if hasDescriptor {
+		crc, expectedSize, err = readZipDataDescriptor(br)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if hash.Sum32() != crc || size != expectedSize {
+		return "", fmt.Errorf("zip entry %s failed verification", name)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", err
+	}
+
+	return target, nil
+}
+
+// readZipDataDescriptor reads the crc and uncompressed size following an entry.
+func readZipDataDescriptor(br *bufio.Reader) (uint32, int64, error) {
+	var fields [3]uint32
+	if err := binary.Read(br, binary.LittleEndian, &fields); err != nil {
+		return 0, 0, fmt.Errorf("error reading zip data descriptor: %w", err)
+	}
+
+	// the signature is optional.
+	if fields[0] == zipDataDescriptorSig {
+		var size uint32
+		if err := binary.Read(br, binary.LittleEndian, &size); err != nil {
+			return 0, 0, fmt.Errorf("error reading zip data descriptor: %w", err)
+		}
+		return fields[1], int64(size), nil
+	}
+
+	return fields[0], int64(fields[2]), nil
+}
+
+// unzipTarget returns where name unpacks to, refusing names that escape dir.
+func unzipTarget(dir string, name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) || len(filepath.VolumeName(clean)) != 0 {
+		return "", fmt.Errorf("zip entry %s is outside the target directory", name)
+	}
+
+	return filepath.Join(dir, clean), nil
+}
@@ -1,7 +1,10 @@
 package monstercat
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 type ImageEncoding string
@@ -9,8 +12,13 @@ type ImageEncoding string
 const (
 	JPEG ImageEncoding = "jpeg"
 	WEBP ImageEncoding = "webp"
+	PNG  ImageEncoding = "png"
+	AVIF ImageEncoding = "avif"
 )
 
+// supported widths, requested widths are rounded up to the nearest one.
+var supportedWidths = []int{64, 128, 256, 300, 512, 640, 768, 1024, 1400, 2048, 3000}
+
 type resizeOptions struct {
 	width    int
 	encoding ImageEncoding
@@ -30,11 +38,26 @@ func (o *resizeOptions) validate() error {
 		return fmt.Errorf("invalid encoding")
 	}
 
+	maxWidth := supportedWidths[len(supportedWidths)-1]
+	if o.width < 1 || o.width > maxWidth {
+		return fmt.Errorf("width must be between 1 and %d", maxWidth)
+	}
+
+	o.width = snapWidth(o.width)
 	return nil
 }
 
+// snapWidth rounds w up to the nearest supported width.
+func snapWidth(w int) int {
+	i := sort.SearchInts(supportedWidths, w)
+	if i == len(supportedWidths) {
+		return supportedWidths[len(supportedWidths)-1]
+	}
+	return supportedWidths[i]
+}
+
 func isEncodingAllowed(encoding ImageEncoding) bool {
-	allowedEncodings := []ImageEncoding{JPEG, WEBP}
+	allowedEncodings := []ImageEncoding{JPEG, WEBP, PNG, AVIF}
 	for _, e := range allowedEncodings {
 		if string(e) == string(encoding) {
 			return true
@@ -54,3 +77,39 @@ func WithEncoding(e ImageEncoding) ResizeOption {
 		o.encoding = e
 	}
 }
+
+// CoverSrcSet returns an HTML srcset for the release cover at the provided widths.
+// Widths are snapped to supported sizes, 256, 512 and 1024 are used when none are provided.
+func (c *Client) CoverSrcSet(ctx context.Context, release Release, widths ...int) (string, error) {
+	if len(release.CoverURL) == 0 {
+		return "", fmt.Errorf("cover url is empty for release")
+	}
+
+	if len(widths) == 0 {
+		widths = []int{256, 512, 1024}
+	}
+
+	seen := make(map[int]bool)
+	candidates := make([]string, 0, len(widths))
+	for _, w := range widths {
+		opts := newResizeOptions()
+		WithWidth(w)(opts)
+		if err := opts.validate(); err != nil {
+			return "", err
+		}
+		if seen[opts.width] {
+			continue
+		}
+		seen[opts.width] = true
+
+		u, err := c.GetResizedImageURL(ctx, release.CoverURL, WithWidth(opts.width))
+		if err != nil {
+			return "", err
+		}
+
+		// commas separate candidates in a srcset.
+		candidates = append(candidates, fmt.Sprintf("%s %dw", strings.ReplaceAll(u, ",", "%2C"), opts.width))
+	}
+
+	return strings.Join(candidates, ", "), nil
+}
@@ -6,13 +6,15 @@ import (
 )
 
 // Search Catalog Results
+//
+// Results decoded from JSON keep their tracks but not their client, so Next returns an error on them.
 type SearchCatalogResults struct {
-	Limit   int
-	Offset  int
-	Size    int
-	Total   int
-	Tracks  []Track
-	HasNext bool
+	Limit   int     `json:"limit,omitempty"`
+	Offset  int     `json:"offset,omitempty"`
+	Size    int     `json:"size,omitempty"`
+	Total   int     `json:"total,omitempty"`
+	Tracks  []Track `json:"tracks,omitempty"`
+	HasNext bool    `json:"hasNext,omitempty"`
 
 	// for next
 	c    *Client
@@ -61,6 +63,10 @@ func (results *SearchCatalogResults) Next(ctx context.Context) (SearchCatalogRes
 	if !results.HasNext {
 		return SearchCatalogResults{}, fmt.Errorf("no further results")
 	}
+	if results.c == nil || results.opts == nil {
+		// decoded results do not know the client and query they came from.
+		return SearchCatalogResults{}, fmt.Errorf("results are not attached to a client")
+	}
 
 	results.opts.offset += results.opts.limit
 	return results.c.searchCatalog(ctx, results.opts.search, results.opts)
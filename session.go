@@ -0,0 +1,226 @@
+package monstercat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+type TwoFactorMethod string
+
+// two factor methods.
+const (
+	TwoFactorTOTP  TwoFactorMethod = "totp"
+	TwoFactorEmail TwoFactorMethod = "email"
+)
+
+// Account.
+type Account struct {
+	ID           string
+	Email        string
+	FirstName    string
+	LastName     string
+	HasGold      bool
+	Subscription Subscription
+}
+
+// Subscription.
+type Subscription struct {
+	Plan    string
+	Status  string
+	Expires time.Time
+}
+
+// Sign In API Response.
+type signInAPIResponse struct {
+	Needs2FA        bool   `json:"Needs2FA"`
+	TwoFactorMethod string `json:"TwoFactorMethod"`
+}
+
+// Me API Response.
+type meAPIResponse struct {
+	User struct {
+		ID        string `json:"Id"`
+		Email     string `json:"Email"`
+		FirstName string `json:"FirstName"`
+		LastName  string `json:"LastName"`
+		HasGold   bool   `json:"HasGold"`
+	} `json:"User"`
+	Subscription struct {
+		PlanName string    `json:"PlanName"`
+		Status   string    `json:"Status"`
+		EndDate  time.Time `json:"EndDate"`
+	} `json:"Subscription"`
+}
+
+func (r *meAPIResponse) toAccount() Account {
+	return Account{
+		ID:        r.User.ID,
+		Email:     r.User.Email,
+		FirstName: r.User.FirstName,
+		LastName:  r.User.LastName,
+		HasGold:   r.User.HasGold,
+		Subscription: Subscription{
+			Plan:    r.Subscription.PlanName,
+			Status:  r.Subscription.Status,
+			Expires: r.Subscription.EndDate,
+		},
+	}
+}
+
+// Login signs in with email and password. The session is kept in the client's
+// cookie jar and used by every later call. When the account has two factor
+// authentication enabled it returns a *TwoFactorError, finish with VerifyTwoFactor.
+func (c *Client) Login(ctx context.Context, email string, password string) error {
+	if len(email) == 0 || len(password) == 0 {
+		return fmt.Errorf("email and password cannot be empty")
+	}
+
+	body := map[string]string{
+		"Email":    email,
+		"Password": password,
+	}
+	req, err := c.makeJSONRequest(ctx, http.MethodPost, "sign-in", body)
+	if err != nil {
+		return err
+	}
+
+	apiResponse := new(signInAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return err
+	}
+
+	if apiResponse.Needs2FA {
+		method := TwoFactorMethod(apiResponse.TwoFactorMethod)
+		if method != TwoFactorEmail {
+			method = TwoFactorTOTP
+		}
+		return &TwoFactorError{Method: method}
+	}
+
+	return nil
+}
+
+// VerifyTwoFactor completes a Login with the code from an authenticator app or email.
+func (c *Client) VerifyTwoFactor(ctx context.Context, method TwoFactorMethod, code string) error {
+	if len(code) == 0 {
+		return fmt.Errorf("code cannot be empty")
+	}
+
+	var path string
+	switch method {
+	case TwoFactorTOTP:
+		path = "sign-in/token"
+	case TwoFactorEmail:
+		path = "sign-in/email-token"
+	default:
+		return fmt.Errorf("invalid two factor method")
+	}
+
+	req, err := c.makeJSONRequest(ctx, http.MethodPost, path, map[string]string{"Token": code})
+	if err != nil {
+		return err
+	}
+
+	return c.doJSON(req, nil)
+}
+
+// Logout ends the session and clears it from the client.
+func (c *Client) Logout(ctx context.Context) error {
+	req, err := c.makeJSONRequest(ctx, http.MethodPost, "sign-out", nil)
+	if err != nil {
+		return err
+	}
+
+	err = c.doJSON(req, nil)
+	c.clearSession()
+	return err
+}
+
+// Me returns the signed in account and its subscription status.
+func (c *Client) Me(ctx context.Context) (Account, error) {
+	req, err := c.makeRequest(ctx, "me", nil)
+	if err != nil {
+		return Account{}, err
+	}
+
+	apiResponse := new(meAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return Account{}, err
+	}
+
+	if len(apiResponse.User.ID) == 0 {
+		return Account{}, ErrUnauthorized
+	}
+
+	return apiResponse.toAccount(), nil
+}
+
+// session cookie as persisted to disk.
+type sessionCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SaveSession writes the session cookies to path, readable only by the current user.
+func (c *Client) SaveSession(path string) error {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return err
+	}
+
+	cookies := make([]sessionCookie, 0)
+	for _, cookie := range c.httpClient.Jar.Cookies(u) {
+		cookies = append(cookies, sessionCookie{Name: cookie.Name, Value: cookie.Value})
+	}
+
+	b, err := json.Marshal(cookies)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o600)
+}
+
+// LoadSession restores session cookies saved with SaveSession.
+func (c *Client) LoadSession(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	saved := make([]sessionCookie, 0)
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return fmt.Errorf("invalid session file: %w", err)
+	}
+
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return err
+	}
+
+	cookies := make([]*http.Cookie, 0, len(saved))
+	for _, cookie := range saved {
+		cookies = append(cookies, &http.Cookie{Name: cookie.Name, Value: cookie.Value, Path: "/"})
+	}
+	c.httpClient.Jar.SetCookies(u, cookies)
+
+	return nil
+}
+
+func (c *Client) clearSession() {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return
+	}
+
+	expired := make([]*http.Cookie, 0)
+	for _, cookie := range c.httpClient.Jar.Cookies(u) {
+		expired = append(expired, &http.Cookie{Name: cookie.Name, Path: "/", MaxAge: -1})
+	}
+	c.httpClient.Jar.SetCookies(u, expired)
+}
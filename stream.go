@@ -0,0 +1,53 @@
+package monstercat
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// streamBody is the reader returned for track streams.
+//
+// It reads straight from the response body so nothing is left running when
+// the caller walks away. Closing it, or cancelling ctx, closes the body and
+// unblocks any pending Read.
+type streamBody struct {
+	ctx  context.Context
+	body io.ReadCloser
+	stop func() bool
+
+	once sync.Once
+	err  error
+}
+
+func newStreamBody(ctx context.Context, body io.ReadCloser) *streamBody {
+	s := &streamBody{
+		ctx:  ctx,
+		body: body,
+	}
+	s.stop = context.AfterFunc(ctx, func() {
+		s.close()
+	})
+	return s
+}
+
+func (s *streamBody) Read(p []byte) (int, error) {
+	n, err := s.body.Read(p)
+	if err != nil && s.ctx.Err() != nil {
+		// report the cancellation rather than the error from the closed body.
+		return n, s.ctx.Err()
+	}
+	return n, err
+}
+
+func (s *streamBody) Close() error {
+	s.stop()
+	return s.close()
+}
+
+func (s *streamBody) close() error {
+	s.once.Do(func() {
+		s.err = s.body.Close()
+	})
+	return s.err
+}
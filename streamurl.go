@@ -0,0 +1,178 @@
+package monstercat
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stream URL.
+//
+// A signed url for a track stream. Expires is zero when the expiry could not
+// be read from the signature.
+type StreamURL struct {
+	URL         string
+	Expires     time.Time
+	ContentType string
+
+	// for refresh
+	c     *Client
+	track Track
+}
+
+// Stream URL API Response.
+type streamURLAPIResponse struct {
+	SignedURL string `json:"SignedURL"`
+}
+
+func (r *streamURLAPIResponse) toStreamURL(c *Client, track Track) (StreamURL, error) {
+	u, err := url.Parse(r.SignedURL)
+	if err != nil || len(r.SignedURL) == 0 {
+		return StreamURL{}, fmt.Errorf("invalid signed url")
+	}
+
+	return StreamURL{
+		URL:         r.SignedURL,
+		Expires:     parseSignedURLExpiry(u.Query()),
+		ContentType: parseSignedURLContentType(u),
+		c:           c,
+		track:       track,
+	}, nil
+}
+
+// ExpiresWithin reports whether the url expires within d. Urls with an unknown expiry never expire.
+func (s *StreamURL) ExpiresWithin(d time.Duration) bool {
+	if s.Expires.IsZero() {
+		return false
+	}
+	return time.Until(s.Expires) <= d
+}
+
+// Refresh re-signs the url for the same track.
+func (s *StreamURL) Refresh(ctx context.Context) error {
+	if s.c == nil {
+		return fmt.Errorf("stream url cannot be refreshed")
+	}
+
+	res, err := s.c.GetTrackStreamURL(ctx, s.track)
+	if err != nil {
+		return err
+	}
+
+	*s = res
+	return nil
+}
+
+// parseSignedURLExpiry reads the expiry from the common signed url schemes.
+func parseSignedURLExpiry(q url.Values) time.Time {
+	// CloudFront and GCS v2 signatures carry a unix timestamp.
+	if v := q.Get("Expires"); len(v) != 0 {
+		if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return time.Unix(sec, 0)
+		}
+	}
+
+	// S3 and GCS v4 signatures carry the signing time and a lifetime in seconds.
+	for _, prefix := range []string{"X-Amz-", "X-Goog-"} {
+		date, expires := q.Get(prefix+"Date"), q.Get(prefix+"Expires")
+		if len(date) == 0 || len(expires) == 0 {
+			continue
+		}
+
+		signed, err := time.Parse("20060102T150405Z", date)
+		if err != nil {
+			continue
+		}
+
+		sec, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		return signed.Add(time.Duration(sec) * time.Second)
+	}
+
+	return time.Time{}
+}
+
+func parseSignedURLContentType(u *url.URL) string {
+	if v := u.Query().Get("response-content-type"); len(v) != 0 {
+		return v
+	}
+	ext := path.Ext(u.Path)
+	if t := mime.TypeByExtension(ext); len(t) != 0 {
+		return t
+	}
+	return audioContentTypes[strings.ToLower(ext)]
+}
+
+// audioContentTypes backs mime.TypeByExtension, which only knows audio types
+// when the system has a mime.types file.
+var audioContentTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".flac": "audio/flac",
+	".wav":  "audio/wav",
+}
+
+// Stream URL Cache.
+//
+// Caches signed stream urls per track and re-signs them shortly before they expire.
+type StreamURLCache struct {
+	c      *Client
+	margin time.Duration
+
+	mu      sync.Mutex
+	entries map[string]StreamURL
+}
+
+// NewStreamURLCache returns a cache that re-signs urls once they are within margin of expiring.
+func NewStreamURLCache(c *Client, margin time.Duration) *StreamURLCache {
+	return &StreamURLCache{
+		c:       c,
+		margin:  margin,
+		entries: make(map[string]StreamURL),
+	}
+}
+
+// Get returns a cached stream url for the track, signing a new one when needed.
+// Urls with an unknown expiry are not cached.
+func (cache *StreamURLCache) Get(ctx context.Context, track Track) (StreamURL, error) {
+	key := track.Release.ID + "/" + track.ID
+
+	cache.mu.Lock()
+	res, ok := cache.entries[key]
+	cache.mu.Unlock()
+
+	if ok && !res.ExpiresWithin(cache.margin) {
+		return res, nil
+	}
+
+	res, err := cache.c.GetTrackStreamURL(ctx, track)
+	if err != nil {
+		return StreamURL{}, err
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// drop expired urls of other tracks too, or a long lived cache grows with every track played.
+	for k, entry := range cache.entries {
+		if entry.ExpiresWithin(0) {
+			delete(cache.entries, k)
+		}
+	}
+
+	if res.Expires.IsZero() {
+		delete(cache.entries, key)
+	} else {
+		cache.entries[key] = res
+	}
+
+	return res, nil
+}
@@ -1,25 +1,46 @@
 package monstercat
 
 import (
+	"encoding/json"
 	"time"
 )
 
 // Track.
+//
+// Marshals to JSON with camelCase keys and RFC 3339 times, leaving empty fields out.
 type Track struct {
-	ID             string
-	Title          string
-	BrandID        int
-	Brand          string
-	DebutDate      time.Time
-	BPM            int
-	Duration       int
-	Explicit       bool
-	GenrePrimary   string
-	GenreSecondary string
-	Public         bool
-	Release        Release
-	ArtistsTitle   string
-	Artists        []Artist
+	ID              string    `json:"id,omitempty"`
+	Title           string    `json:"title,omitempty"`
+	ISRC            string    `json:"isrc,omitempty"`
+	TrackNumber     int       `json:"trackNumber,omitempty"`
+	BrandID         int       `json:"brandId,omitempty"`
+	Brand           string    `json:"brand,omitempty"`
+	DebutDate       time.Time `json:"debutDate"`
+	BPM             int       `json:"bpm,omitempty"`
+	Duration        int       `json:"duration,omitempty"`
+	Explicit        bool      `json:"explicit,omitempty"`
+	GenrePrimary    string    `json:"genrePrimary,omitempty"`
+	GenreSecondary  string    `json:"genreSecondary,omitempty"`
+	Public          bool      `json:"public,omitempty"`
+	Downloadable    bool      `json:"downloadable,omitempty"`
+	CreatorFriendly bool      `json:"creatorFriendly,omitempty"`
+	Release         Release   `json:"release"`
+	ArtistsTitle    string    `json:"artistsTitle,omitempty"`
+	Artists         []Artist  `json:"artists,omitempty"`
+}
+
+// MarshalJSON leaves out the debut date and release when they are zero.
+func (t Track) MarshalJSON() ([]byte, error) {
+	type track Track
+	out := struct {
+		track
+		DebutDate *time.Time `json:"debutDate,omitempty"`
+		Release   *Release   `json:"release,omitempty"`
+	}{track: track(t), DebutDate: timeOrNil(t.DebutDate)}
+	if t.Release != (Release{}) {
+		out.Release = &t.Release
+	}
+	return json.Marshal(out)
 }
 
 // Track API Response.
@@ -57,19 +78,23 @@ func (r *trackAPIResponse) toTrack() Track {
 	}
 
 	return Track{
-		ID:             r.ID,
-		Title:          r.Title,
-		BrandID:        r.BrandID,
-		Brand:          r.Brand,
-		DebutDate:      r.DebutDate,
-		BPM:            r.BPM,
-		Duration:       r.Duration,
-		Explicit:       r.Explicit,
-		GenrePrimary:   r.GenrePrimary,
-		GenreSecondary: r.GenreSecondary,
-		Public:         r.Public,
-		Release:        r.Release.toRelease(),
-		ArtistsTitle:   r.ArtistsTitle,
-		Artists:        artists,
+		ID:              r.ID,
+		Title:           r.Title,
+		ISRC:            r.ISRC,
+		TrackNumber:     r.TrackNumber,
+		BrandID:         r.BrandID,
+		Brand:           r.Brand,
+		DebutDate:       r.DebutDate,
+		BPM:             r.BPM,
+		Duration:        r.Duration,
+		Explicit:        r.Explicit,
+		GenrePrimary:    r.GenrePrimary,
+		GenreSecondary:  r.GenreSecondary,
+		Public:          r.Public,
+		Downloadable:    r.Downloadable,
+		CreatorFriendly: r.CreatorFriendly,
+		Release:         r.Release.toRelease(),
+		ArtistsTitle:    r.ArtistsTitle,
+		Artists:         artists,
 	}
 }
@@ -0,0 +1,82 @@
+package monstercat
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Track Page.
+//
+// A page of tracks from a paginated listing such as a playlist.
+type TrackPage struct {
+	Limit   int
+	Offset  int
+	Size    int
+	Total   int
+	Tracks  []Track
+	HasNext bool
+
+	// for next
+	fetch func(ctx context.Context, opts *options) (TrackPage, error)
+	opts  *options
+}
+
+// Next returns the following page.
+func (page *TrackPage) Next(ctx context.Context) (TrackPage, error) {
+	if !page.HasNext || page.fetch == nil {
+		return TrackPage{}, fmt.Errorf("no further results")
+	}
+
+	page.opts.offset += page.opts.limit
+	return page.fetch(ctx, page.opts)
+}
+
+func (r *searchCatalogAPIResponse) toTrackPage(fetch func(ctx context.Context, opts *options) (TrackPage, error), opts *options) TrackPage {
+	tracks := make([]Track, 0)
+	for _, result := range r.Data {
+		tracks = append(tracks, result.toTrack())
+	}
+
+	hasNext := (len(tracks) + r.Offset) < r.Total
+	page := TrackPage{
+		Limit:   r.Limit,
+		Offset:  r.Offset,
+		Size:    len(tracks),
+		Total:   r.Total,
+		Tracks:  tracks,
+		HasNext: hasNext,
+	}
+	if hasNext {
+		page.fetch = fetch
+		page.opts = opts
+	}
+
+	return page
+}
+
+// getTrackPage fetches one page of tracks from a listing endpoint.
+func (c *Client) getTrackPage(ctx context.Context, url string, opts *options) (TrackPage, error) {
+	if err := opts.validate(); err != nil {
+		return TrackPage{}, err
+	}
+
+	params := make(map[string]string)
+	params["limit"] = strconv.Itoa(opts.limit)
+	params["offset"] = strconv.Itoa(opts.offset)
+
+	req, err := c.makeRequest(ctx, url, params)
+	if err != nil {
+		return TrackPage{}, err
+	}
+
+	apiResponse := new(searchCatalogAPIResponse)
+	if err := c.doJSON(req, apiResponse); err != nil {
+		return TrackPage{}, err
+	}
+
+	fetch := func(ctx context.Context, opts *options) (TrackPage, error) {
+		return c.getTrackPage(ctx, url, opts)
+	}
+	return apiResponse.toTrackPage(fetch, opts), nil
+}